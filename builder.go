@@ -0,0 +1,176 @@
+package goqs
+
+import (
+	"net/url"
+	"strings"
+)
+
+// URLBuilder builds a URL with nested query parameters. Its methods return
+// the builder for chaining; the first error is kept and reported by Build.
+// Parameters of the base URL are written back exactly as given unless the
+// builder changes their root key.
+type URLBuilder struct {
+	url     *url.URL
+	query   map[string]interface{}
+	base    []baseParam
+	touched map[string]bool
+	options StringifyOptions
+	err     error
+}
+
+// baseParam is a raw parameter of the base URL and the root key it parses to.
+type baseParam struct {
+	raw  string
+	root string
+}
+
+// NewURL starts a builder from base, parsing its query with the default
+// ParseOptions. Parameters are percent-encoded with the RFC3986 formatter by
+// default.
+func NewURL(base string) *URLBuilder {
+	return NewURLWithOptions(base, nil)
+}
+
+// NewURLWithOptions starts a builder from base, parsing its query with opts,
+// e.g. with LiteralPlus for signed values.
+func NewURLWithOptions(base string, opts *ParseOptions) *URLBuilder {
+	b := &URLBuilder{
+		options: stringifyDefaults,
+		query:   map[string]interface{}{},
+		touched: map[string]bool{},
+	}
+	parsed, err := ParseURL(base, &URLOptions{Parse: opts})
+	if err != nil {
+		b.url = &url.URL{}
+		b.err = err
+		return b
+	}
+	b.url = parsed.URL
+	b.query = parsed.Query
+	b.base = splitBaseParams(parsed.URL.RawQuery, normalizeParseOptions(opts))
+	return b
+}
+
+// splitBaseParams splits a raw query into its parameters and their root keys.
+func splitBaseParams(rawQuery string, options ParseOptions) []baseParam {
+	if rawQuery == "" {
+		return nil
+	}
+	// Only keys are needed
+	options.ValueDecoder = nil
+
+	var params []baseParam
+	for _, raw := range strings.Split(rawQuery, options.Delimiter) {
+		param := baseParam{raw: raw}
+		if values := parseValues(escapeQueryString(raw, options.LiteralPlus), options); len(values) > 0 {
+			if chain := splitKey(values[0].key, options); len(chain) > 0 {
+				param.root = chainPath(chain)[0]
+			}
+		}
+		params = append(params, param)
+	}
+	return params
+}
+
+// touch marks the root key of path as changed by the builder.
+func (b *URLBuilder) touch(path string) {
+	if segments := splitPath(path); len(segments) > 0 {
+		b.touched[segments[0]] = true
+	}
+}
+
+// Set stores value at a dot or bracket path, as Set does.
+func (b *URLBuilder) Set(path string, value interface{}) *URLBuilder {
+	if b.err == nil {
+		b.touch(path)
+		b.err = Set(b.query, path, value)
+	}
+	return b
+}
+
+// Add appends value to the array at path, as Append does. A trailing "[]"
+// on path is optional.
+func (b *URLBuilder) Add(path string, value interface{}) *URLBuilder {
+	if b.err == nil {
+		b.touch(path)
+		b.err = Append(b.query, strings.TrimSuffix(path, "[]"), value)
+	}
+	return b
+}
+
+// Merge deep-merges params into the query, params winning on conflicts.
+func (b *URLBuilder) Merge(params map[string]interface{}) *URLBuilder {
+	for k := range params {
+		b.touched[k] = true
+	}
+	b.query = MergeQueries(b.query, params, OverrideWins)
+	return b
+}
+
+// Delete removes the parameter at path.
+func (b *URLBuilder) Delete(path string) *URLBuilder {
+	b.touch(path)
+	Delete(b.query, path)
+	return b
+}
+
+// ArrayFormat sets how arrays are serialized, ArrayFormatIndices by default.
+func (b *URLBuilder) ArrayFormat(format ArrayFormat) *URLBuilder {
+	b.options.ArrayFormat = format
+	return b
+}
+
+// Format sets the percent-encoding format, RFC3986 by default.
+func (b *URLBuilder) Format(format RFCFormat) *URLBuilder {
+	b.options.Format = format
+	return b
+}
+
+// Sort sets the order of the keys the builder writes, lexical by default.
+// Untouched base parameters keep their place ahead of them.
+func (b *URLBuilder) Sort(less func(a, b string) bool) *URLBuilder {
+	b.options.Sort = less
+	return b
+}
+
+// Build returns a copy of the base URL with the built query string: the
+// untouched base parameters as given, followed by the changed ones.
+func (b *URLBuilder) Build() (*url.URL, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	var parts []string
+	for _, param := range b.base {
+		if !b.touched[param.root] {
+			parts = append(parts, param.raw)
+		}
+	}
+	changed := map[string]interface{}{}
+	for k := range b.touched {
+		if v, ok := b.query[k]; ok {
+			changed[k] = v
+		}
+	}
+	query, err := Stringify(changed, &b.options)
+	if err != nil {
+		return nil, err
+	}
+	if query != "" {
+		parts = append(parts, query)
+	}
+
+	u := *b.url
+	u.RawQuery = strings.Join(parts, b.options.Delimiter)
+	u.ForceQuery = false
+	return &u, nil
+}
+
+// String returns the built URL, or an empty string if building failed.
+func (b *URLBuilder) String() string {
+	u, err := b.Build()
+	if err != nil {
+		return ""
+	}
+	return u.String()
+}
@@ -0,0 +1,91 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLBuilder(t *testing.T) {
+	got := NewURL("https://api.example.com/v1/items?page=2#top").
+		Set("filter[status]", "open").
+		Add("tags[]", "x").
+		Add("tags", "y z").
+		Merge(map[string]interface{}{"page": "3", "filter": map[string]interface{}{"owner": "a&b"}}).
+		String()
+	assert.Equal(t, "https://api.example.com/v1/items?filter%5Bowner%5D=a%26b&filter%5Bstatus%5D=open&page=3&tags%5B0%5D=x&tags%5B1%5D=y%20z#top", got)
+}
+
+func TestURLBuilderKeepsBaseParams(t *testing.T) {
+	base := "https://partner.example.com/v2?id=1&id=2&sig=ab+cd%2Bef&flag"
+
+	assert.Equal(t, base, NewURL(base).String())
+	assert.Equal(t, base+"&filter%5Bstatus%5D=open", NewURL(base).Set("filter[status]", "open").String())
+	assert.Equal(t, "https://partner.example.com/v2?id=1&id=2&sig=ab+cd%2Bef&page=3", NewURL(base).Delete("flag").Set("page", "3").String())
+	assert.Equal(t, "https://partner.example.com/v2?sig=ab+cd%2Bef&flag&id%5B0%5D=1&id%5B1%5D=2&id%5B2%5D=3", NewURL(base).Add("id", "3").String())
+}
+
+func TestNewURLWithOptions(t *testing.T) {
+	base := "https://partner.example.com/v2?sig=ab+cd&a.b=1"
+
+	b := NewURLWithOptions(base, &ParseOptions{LiteralPlus: true, AllowDots: true, Depth: 5, ArrayLimit: 20, ParseArrays: true})
+	assert.Equal(t, base, b.String())
+
+	b.Set("sig", "ab+cd+ef").Set("a.c", "2")
+	assert.Equal(t, "https://partner.example.com/v2?a%5Bb%5D=1&a%5Bc%5D=2&sig=ab%2Bcd%2Bef", b.String())
+}
+
+func TestURLBuilderOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		builder  *URLBuilder
+		expected string
+	}{
+		{
+			name:     "brackets",
+			builder:  NewURL("/search").Add("a", "1").Add("a", "2").ArrayFormat(ArrayFormatBrackets),
+			expected: "/search?a%5B%5D=1&a%5B%5D=2",
+		},
+		{
+			name:     "comma",
+			builder:  NewURL("/search").Set("a", []string{"1", "2"}).ArrayFormat(ArrayFormatComma),
+			expected: "/search?a=1,2",
+		},
+		{
+			name:     "RFC1738",
+			builder:  NewURL("/search").Set("q", "a b").Format(RFC1738),
+			expected: "/search?q=a+b",
+		},
+		{
+			name:     "sort",
+			builder:  NewURL("/search").Set("a", "1").Set("b", "2").Sort(func(a, b string) bool { return a > b }),
+			expected: "/search?b=2&a=1",
+		},
+		{
+			name:     "delete",
+			builder:  NewURL("/search?a=1&b=2").Delete("a"),
+			expected: "/search?b=2",
+		},
+		{
+			name:     "no params",
+			builder:  NewURL("/search?"),
+			expected: "/search",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.builder.String())
+		})
+	}
+}
+
+func TestURLBuilderErrors(t *testing.T) {
+	_, err := NewURL("http://[::1").Set("a", "1").Build()
+	assert.Error(t, err)
+
+	b := NewURL("/search").Set("a", "1").Set("a[b]", "2")
+	_, err = b.Build()
+	assert.Error(t, err)
+	assert.Equal(t, "", b.String())
+}
@@ -0,0 +1,76 @@
+package goqs
+
+// CanonicalOptions configures Canonicalize.
+type CanonicalOptions struct {
+	// Parse holds the options used to parse the query, the defaults if nil.
+	Parse *ParseOptions
+	// ArrayFormat is the array format of the output, ArrayFormatIndices if
+	// empty.
+	ArrayFormat ArrayFormat
+	// DropEmpty omits parameters with empty or null values.
+	DropEmpty bool
+}
+
+// Canonicalize parses query and re-emits it in a canonical form suitable for
+// cache keys and signatures: keys sorted, one array format, RFC3986
+// percent-encoding with uppercase hex and unreserved characters left raw.
+// Queries that parse to the same result produce the same string, so
+// "a[]=1&b=2" and "b=2&a%5B0%5D=1" both become "a%5B0%5D=1&b=2".
+func Canonicalize(query string, opts *CanonicalOptions) (string, error) {
+	var options CanonicalOptions
+	if opts != nil {
+		options = *opts
+	}
+
+	result, err := Parse(query, options.Parse)
+	if err != nil {
+		return "", err
+	}
+	if options.DropEmpty {
+		dropEmpty(result)
+	}
+
+	return Stringify(result, &StringifyOptions{
+		ArrayFormat: options.ArrayFormat,
+		Format:      RFC3986,
+	})
+}
+
+// dropEmpty removes empty and null leaves from value, and the objects and
+// arrays they leave empty. It returns the pruned value and whether it is
+// empty itself.
+func dropEmpty(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return v, true
+	case string:
+		return v, v == ""
+	case map[string]interface{}:
+		for k, item := range v {
+			if pruned, empty := dropEmpty(item); empty {
+				delete(v, k)
+			} else {
+				v[k] = pruned
+			}
+		}
+		return v, len(v) == 0
+	case map[int]interface{}:
+		for k, item := range v {
+			if pruned, empty := dropEmpty(item); empty {
+				delete(v, k)
+			} else {
+				v[k] = pruned
+			}
+		}
+		return v, len(v) == 0
+	case []interface{}:
+		kept := []interface{}{}
+		for _, item := range v {
+			if pruned, empty := dropEmpty(item); !empty {
+				kept = append(kept, pruned)
+			}
+		}
+		return kept, len(kept) == 0
+	}
+	return value, false
+}
@@ -0,0 +1,59 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		queries  []string
+		options  *CanonicalOptions
+		expected string
+	}{
+		{
+			name:     "Array notations and key order",
+			queries:  []string{"a[]=1&b=2", "b=2&a%5B0%5D=1", "b=2&a[0]=1"},
+			expected: "a%5B0%5D=1&b=2",
+		},
+		{
+			name:     "Percent-encoding",
+			queries:  []string{"q=hello world", "q=hello+world", "q=hello%20world"},
+			expected: "q=hello%20world",
+		},
+		{
+			name:     "Uppercase hex and raw unreserved characters",
+			queries:  []string{"q=%c3%a9%7e", "q=é~"},
+			expected: "q=%C3%A9~",
+		},
+		{
+			name:     "Nested objects",
+			queries:  []string{"f[z]=1&f[a]=2", "f[a]=2&f[z]=1"},
+			expected: "f%5Ba%5D=2&f%5Bz%5D=1",
+		},
+		{
+			name:     "Brackets array format",
+			queries:  []string{"a[1]=x&a[2]=y", "a[]=x&a[]=y"},
+			options:  &CanonicalOptions{ArrayFormat: ArrayFormatBrackets},
+			expected: "a%5B%5D=x&a%5B%5D=y",
+		},
+		{
+			name:     "Dropped empty parameters",
+			queries:  []string{"a=&b=1&c[]=&c[]=2&d[e]=", "b=1&c[]=2"},
+			options:  &CanonicalOptions{DropEmpty: true},
+			expected: "b=1&c%5B0%5D=2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, query := range tt.queries {
+				res, err := Canonicalize(query, tt.options)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expected, res, query)
+			}
+		})
+	}
+}
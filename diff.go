@@ -0,0 +1,148 @@
+package goqs
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Change is a difference at a single key path. Segments holds the path's
+// keys and array indices as found in the parsed result, and Path displays
+// them in bracket notation such as "filter[status]" or "tags[1]". Old is nil
+// for added paths and New is nil for removed ones.
+type Change struct {
+	Path     string
+	Segments []string
+	Old      interface{}
+	New      interface{}
+}
+
+// QueryDiff lists the differences between two queries. Object keys are
+// compared in lexical order and array items by index.
+type QueryDiff struct {
+	Added   []Change
+	Removed []Change
+	Changed []Change
+}
+
+// Empty reports whether the queries were equivalent.
+func (d *QueryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff parses a and b with opts and returns the key paths added, removed and
+// changed from a to b.
+func Diff(a, b string, opts *ParseOptions) (*QueryDiff, error) {
+	from, err := Parse(a, opts)
+	if err != nil {
+		return nil, err
+	}
+	to, err := Parse(b, opts)
+	if err != nil {
+		return nil, err
+	}
+	d := &QueryDiff{}
+	d.compare(nil, from, to)
+	return d, nil
+}
+
+func (d *QueryDiff) compare(path []string, from, to interface{}) {
+	fromMap, fromIsMap := from.(map[string]interface{})
+	toMap, toIsMap := to.(map[string]interface{})
+	if fromIsMap && toIsMap {
+		keys := make([]string, 0, len(fromMap)+len(toMap))
+		for k := range fromMap {
+			keys = append(keys, k)
+		}
+		for k := range toMap {
+			if _, ok := fromMap[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fromValue, inFrom := fromMap[k]
+			toValue, inTo := toMap[k]
+			childPath := childSegments(path, k)
+			switch {
+			case !inFrom:
+				d.Added = append(d.Added, newChange(childPath, nil, toValue))
+			case !inTo:
+				d.Removed = append(d.Removed, newChange(childPath, fromValue, nil))
+			default:
+				d.compare(childPath, fromValue, toValue)
+			}
+		}
+		return
+	}
+
+	fromItems, fromIsArray := from.([]interface{})
+	toItems, toIsArray := to.([]interface{})
+	if fromIsArray && toIsArray {
+		for i := 0; i < len(fromItems) || i < len(toItems); i++ {
+			childPath := childSegments(path, strconv.Itoa(i))
+			switch {
+			case i >= len(fromItems):
+				d.Added = append(d.Added, newChange(childPath, nil, toItems[i]))
+			case i >= len(toItems):
+				d.Removed = append(d.Removed, newChange(childPath, fromItems[i], nil))
+			default:
+				d.compare(childPath, fromItems[i], toItems[i])
+			}
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(from, to) {
+		d.Changed = append(d.Changed, newChange(path, from, to))
+	}
+}
+
+// childSegments returns a copy of path extended with key.
+func childSegments(path []string, key string) []string {
+	return append(append(make([]string, 0, len(path)+1), path...), key)
+}
+
+func newChange(path []string, oldValue, newValue interface{}) Change {
+	display := path[0]
+	for _, segment := range path[1:] {
+		display += "[" + segment + "]"
+	}
+	return Change{Path: display, Segments: path, Old: oldValue, New: newValue}
+}
+
+// Patch applies d to query and returns the resulting query string. Changes
+// are applied first, then removals in reverse order and additions in order,
+// so array items are removed and added at the right indices. Changes are
+// located by their Segments, so keys holding dots or brackets are kept.
+func Patch(query string, d *QueryDiff, opts *ParseOptions) (string, error) {
+	result, err := Parse(query, opts)
+	if err != nil {
+		return "", err
+	}
+	for _, change := range d.Changed {
+		if err := applyChange(result, change); err != nil {
+			return "", err
+		}
+	}
+	for i := len(d.Removed) - 1; i >= 0; i-- {
+		deleteIn(result, d.Removed[i].Segments)
+	}
+	for _, change := range d.Added {
+		if err := applyChange(result, change); err != nil {
+			return "", err
+		}
+	}
+	return Stringify(result, nil)
+}
+
+func applyChange(result map[string]interface{}, change Change) error {
+	if len(change.Segments) == 0 {
+		return &PathError{Path: change.Path, Err: errors.New("empty path")}
+	}
+	if _, err := setIn(result, change.Segments, normalizeValue(cloneValue(change.New))); err != nil {
+		return &PathError{Path: change.Path, Err: err}
+	}
+	return nil
+}
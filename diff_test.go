@@ -0,0 +1,99 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	d, err := Diff(
+		"page=1&filter[status]=open&tags[]=a&tags[]=b&utm=x",
+		"page=2&filter[status]=open&filter[owner]=me&tags[]=a&tags[]=c&tags[]=d",
+		nil,
+	)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []Change{
+		{Path: "filter[owner]", Segments: []string{"filter", "owner"}, New: "me"},
+		{Path: "tags[2]", Segments: []string{"tags", "2"}, New: "d"},
+	}, d.Added)
+	assert.Equal(t, []Change{
+		{Path: "utm", Segments: []string{"utm"}, Old: "x"},
+	}, d.Removed)
+	assert.Equal(t, []Change{
+		{Path: "page", Segments: []string{"page"}, Old: "1", New: "2"},
+		{Path: "tags[1]", Segments: []string{"tags", "1"}, Old: "b", New: "c"},
+	}, d.Changed)
+	assert.False(t, d.Empty())
+
+	d, err = Diff("a=1&b=2", "b=2&a=1", nil)
+	assert.NoError(t, err)
+	assert.True(t, d.Empty())
+}
+
+func TestDiffTypeChange(t *testing.T) {
+	d, err := Diff("a=1", "a[b]=1", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "a", Segments: []string{"a"}, Old: "1", New: map[string]interface{}{"b": "1"}}}, d.Changed)
+}
+
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+	}{
+		{name: "Scalars and nested keys", a: "page=1&filter[status]=open&utm=x", b: "page=2&filter[status]=open&filter[owner]=me"},
+		{name: "Growing arrays", a: "tags[]=a", b: "tags[]=a&tags[]=b&tags[]=c"},
+		{name: "Shrinking arrays", a: "tags[]=a&tags[]=b&tags[]=c", b: "tags[]=b"},
+		{name: "Type changes", a: "a=1&b[c]=2", b: "a[x]=1&b=2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Diff(tt.a, tt.b, nil)
+			assert.NoError(t, err)
+
+			patched, err := Patch(tt.a, d, nil)
+			assert.NoError(t, err)
+
+			expected, err := Canonicalize(tt.b, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, patched)
+		})
+	}
+}
+
+func TestPatchLiteralKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		options *ParseOptions
+	}{
+		{name: "Dotted key", a: "x=1", b: "x=1&a.b=2"},
+		{name: "Dotted key change", a: "a.b=1&c[d.e]=1", b: "a.b=2&c[d.e]=3"},
+		{name: "Bracketed key past depth", a: "x=1", b: "x=1&a[b][c]=2", options: &ParseOptions{Depth: 1, ArrayLimit: 20, ParseArrays: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := Diff(tt.a, tt.b, tt.options)
+			assert.NoError(t, err)
+
+			patched, err := Patch(tt.a, d, tt.options)
+			assert.NoError(t, err)
+
+			target, err := Parse(tt.b, tt.options)
+			assert.NoError(t, err)
+			expected, err := Stringify(target, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, patched)
+		})
+	}
+
+	d, err := Diff("x=1", "x=1&a.b=2", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{{Path: "a.b", Segments: []string{"a.b"}, New: "2"}}, d.Added)
+}
@@ -0,0 +1,192 @@
+// Package filter converts the nested operator maps produced by goqs.Parse,
+// such as price[gte]=10&status[in]=a,b, into typed filter expressions.
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	goqs "github.com/globocom/go-qs"
+)
+
+// Operator names a comparison applied to a field.
+type Operator string
+
+const (
+	Eq      Operator = "eq"
+	Neq     Operator = "neq"
+	Lt      Operator = "lt"
+	Lte     Operator = "lte"
+	Gt      Operator = "gt"
+	Gte     Operator = "gte"
+	Like    Operator = "like"
+	In      Operator = "in"
+	Between Operator = "between"
+)
+
+// Arity describes how many operands an operator takes.
+type Arity int
+
+const (
+	// Single operators take exactly one operand.
+	Single Arity = iota
+	// List operators take one or more comma separated operands.
+	List
+	// Pair operators take exactly two comma separated operands.
+	Pair
+)
+
+// OperatorSet maps the operators accepted in queries to their arity.
+type OperatorSet map[Operator]Arity
+
+// DefaultOperators extends the operators recognised by goqs.QsOperatorRules
+// with eq, gte and lte.
+var DefaultOperators = OperatorSet{
+	Eq:      Single,
+	Neq:     Single,
+	Lt:      Single,
+	Lte:     Single,
+	Gt:      Single,
+	Gte:     Single,
+	Like:    Single,
+	In:      List,
+	Between: Pair,
+}
+
+// Condition compares a field against its operands. Nested fields are joined
+// with dots, so user[age][gte]=18 has the field "user.age".
+type Condition struct {
+	Field    string
+	Operator Operator
+	Operands []string
+}
+
+// Expression is the conjunction of its conditions.
+type Expression struct {
+	Conditions []Condition
+}
+
+// Options configures Build.
+type Options struct {
+	Operators OperatorSet
+}
+
+// UnknownOperatorError is returned when a query uses an operator missing from
+// the operator set.
+type UnknownOperatorError struct {
+	Field    string
+	Operator string
+}
+
+func (e *UnknownOperatorError) Error() string {
+	return fmt.Sprintf("filter: unknown operator %q for field %q", e.Operator, e.Field)
+}
+
+// OperandError is returned when an operator receives the wrong number of
+// operands or a value that is not a string.
+type OperandError struct {
+	Field    string
+	Operator Operator
+	Reason   string
+}
+
+func (e *OperandError) Error() string {
+	return fmt.Sprintf("filter: %s %s: %s", e.Field, e.Operator, e.Reason)
+}
+
+// Build converts a parsed query into an expression. Top-level values compare
+// with Eq, or In for arrays; below the top level the last key of every
+// parameter names its operator. Conditions are sorted by field and operator.
+func Build(parsed map[string]interface{}, opts *Options) (*Expression, error) {
+	operators := DefaultOperators
+	if opts != nil && opts.Operators != nil {
+		operators = opts.Operators
+	}
+
+	expr := &Expression{}
+	for _, field := range sortedKeys(parsed) {
+		var err error
+		switch v := parsed[field].(type) {
+		case map[string]interface{}:
+			err = expr.addFields(field, v, operators)
+		case []interface{}:
+			err = expr.add(field, string(In), v, operators)
+		default:
+			err = expr.add(field, string(Eq), v, operators)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expr, nil
+}
+
+// addFields walks a nested map, treating keys holding maps as nested fields
+// and every other key as an operator.
+func (e *Expression) addFields(field string, m map[string]interface{}, operators OperatorSet) error {
+	for _, key := range sortedKeys(m) {
+		var err error
+		if nested, ok := m[key].(map[string]interface{}); ok {
+			err = e.addFields(field+"."+key, nested, operators)
+		} else {
+			err = e.add(field, key, m[key], operators)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *Expression) add(field, name string, value interface{}, operators OperatorSet) error {
+	op := Operator(name)
+	arity, ok := operators[op]
+	if !ok {
+		return &UnknownOperatorError{Field: field, Operator: name}
+	}
+
+	var operands []string
+	switch v := value.(type) {
+	case string:
+		if arity == Single {
+			operands = []string{v}
+		} else {
+			operands = strings.Split(v, ",")
+		}
+	case []interface{}:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return &OperandError{Field: field, Operator: op, Reason: "nested values are not supported"}
+			}
+			operands = append(operands, s)
+		}
+	default:
+		if v == nil {
+			return &OperandError{Field: field, Operator: op, Reason: "missing operand"}
+		}
+		operands = []string{goqs.AsString(v)}
+	}
+
+	switch {
+	case arity == Single && len(operands) != 1:
+		return &OperandError{Field: field, Operator: op, Reason: fmt.Sprintf("expected 1 operand, got %d", len(operands))}
+	case arity == Pair && len(operands) != 2:
+		return &OperandError{Field: field, Operator: op, Reason: fmt.Sprintf("expected 2 operands, got %d", len(operands))}
+	case arity == List && len(operands) == 0:
+		return &OperandError{Field: field, Operator: op, Reason: "expected at least 1 operand"}
+	}
+
+	e.Conditions = append(e.Conditions, Condition{Field: field, Operator: op, Operands: operands})
+	return nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,77 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	goqs "github.com/globocom/go-qs"
+	"github.com/stretchr/testify/assert"
+)
+
+func parse(t *testing.T, query string) map[string]interface{} {
+	t.Helper()
+	result, err := goqs.Parse(query, &goqs.ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []Condition
+	}{
+		{
+			name:  "Bracket operators",
+			query: "price[gte]=10&price[lt]=50&status[in]=a,b&name[like]=foo",
+			expected: []Condition{
+				{Field: "name", Operator: Like, Operands: []string{"foo"}},
+				{Field: "price", Operator: Gte, Operands: []string{"10"}},
+				{Field: "price", Operator: Lt, Operands: []string{"50"}},
+				{Field: "status", Operator: In, Operands: []string{"a", "b"}},
+			},
+		},
+		{
+			name:  "Implicit operators",
+			query: "status=open&tags[]=x&tags[]=y",
+			expected: []Condition{
+				{Field: "status", Operator: Eq, Operands: []string{"open"}},
+				{Field: "tags", Operator: In, Operands: []string{"x", "y"}},
+			},
+		},
+		{
+			name:  "Nested fields",
+			query: "user[age][between]=18,30&user[name][eq]=bob",
+			expected: []Condition{
+				{Field: "user.age", Operator: Between, Operands: []string{"18", "30"}},
+				{Field: "user.name", Operator: Eq, Operands: []string{"bob"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Build(parse(t, tt.query), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, expr.Conditions)
+		})
+	}
+}
+
+func TestBuildErrors(t *testing.T) {
+	_, err := Build(parse(t, "price[approx]=10"), nil)
+	var unknownErr *UnknownOperatorError
+	if assert.True(t, errors.As(err, &unknownErr)) {
+		assert.Equal(t, "price", unknownErr.Field)
+		assert.Equal(t, "approx", unknownErr.Operator)
+	}
+
+	_, err = Build(parse(t, "price[between]=10"), nil)
+	var operandErr *OperandError
+	assert.True(t, errors.As(err, &operandErr))
+
+	_, err = Build(parse(t, "price[gte]=10"), &Options{Operators: OperatorSet{Lt: Single}})
+	assert.True(t, errors.As(err, &unknownErr))
+}
@@ -0,0 +1,115 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dialect selects the placeholder style of rendered SQL.
+type Dialect int
+
+const (
+	// Postgres numbers placeholders as $1, $2, ...
+	Postgres Dialect = iota
+	// MySQL uses positional ? placeholders.
+	MySQL
+	// SQLite numbers placeholders as ?1, ?2, ...
+	SQLite
+)
+
+// DefaultSQLOperators maps the default operators to SQL.
+var DefaultSQLOperators = map[Operator]string{
+	Eq:      "=",
+	Neq:     "<>",
+	Lt:      "<",
+	Lte:     "<=",
+	Gt:      ">",
+	Gte:     ">=",
+	Like:    "LIKE",
+	In:      "IN",
+	Between: "BETWEEN",
+}
+
+// SQLOptions configures RenderSQL.
+type SQLOptions struct {
+	Dialect Dialect
+	// Columns maps fields to the SQL columns they are compared with. It is
+	// the allowlist of filterable fields: any other field is rejected.
+	Columns map[string]string
+	// Operators gives the arity of every operator, DefaultOperators if nil.
+	Operators OperatorSet
+	// SQLOperators maps operators to SQL, DefaultSQLOperators if nil.
+	SQLOperators map[Operator]string
+}
+
+// UnknownFieldError is returned by RenderSQL for fields missing from
+// SQLOptions.Columns.
+type UnknownFieldError struct {
+	Field string
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("filter: field %q is not filterable", e.Field)
+}
+
+// RenderSQL renders expr as a parameterized WHERE clause, e.g.
+// "WHERE price >= $1 AND status IN ($2,$3)", and returns the clause with its
+// arguments. An empty expression renders as an empty string.
+func RenderSQL(expr *Expression, opts SQLOptions) (string, []interface{}, error) {
+	if expr == nil || len(expr.Conditions) == 0 {
+		return "", nil, nil
+	}
+	operators := opts.Operators
+	if operators == nil {
+		operators = DefaultOperators
+	}
+	sqlOperators := opts.SQLOperators
+	if sqlOperators == nil {
+		sqlOperators = DefaultSQLOperators
+	}
+
+	var args []interface{}
+	placeholder := func(operand string) string {
+		args = append(args, operand)
+		switch opts.Dialect {
+		case Postgres:
+			return "$" + strconv.Itoa(len(args))
+		case SQLite:
+			return "?" + strconv.Itoa(len(args))
+		}
+		return "?"
+	}
+
+	clauses := make([]string, 0, len(expr.Conditions))
+	for _, cond := range expr.Conditions {
+		column, ok := opts.Columns[cond.Field]
+		if !ok {
+			return "", nil, &UnknownFieldError{Field: cond.Field}
+		}
+		sqlOp, ok := sqlOperators[cond.Operator]
+		if !ok {
+			return "", nil, &UnknownOperatorError{Field: cond.Field, Operator: string(cond.Operator)}
+		}
+		arity, ok := operators[cond.Operator]
+		if !ok {
+			return "", nil, &UnknownOperatorError{Field: cond.Field, Operator: string(cond.Operator)}
+		}
+
+		switch {
+		case arity == Single && len(cond.Operands) == 1:
+			clauses = append(clauses, column+" "+sqlOp+" "+placeholder(cond.Operands[0]))
+		case arity == Pair && len(cond.Operands) == 2:
+			clauses = append(clauses, column+" "+sqlOp+" "+placeholder(cond.Operands[0])+" AND "+placeholder(cond.Operands[1]))
+		case arity == List && len(cond.Operands) > 0:
+			placeholders := make([]string, len(cond.Operands))
+			for i, operand := range cond.Operands {
+				placeholders[i] = placeholder(operand)
+			}
+			clauses = append(clauses, column+" "+sqlOp+" ("+strings.Join(placeholders, ",")+")")
+		default:
+			return "", nil, &OperandError{Field: cond.Field, Operator: cond.Operator, Reason: fmt.Sprintf("unexpected %d operands", len(cond.Operands))}
+		}
+	}
+	return "WHERE " + strings.Join(clauses, " AND "), args, nil
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderSQL(t *testing.T) {
+	columns := map[string]string{"price": "p.price", "status": "p.status", "name": "p.name", "user.age": "u.age"}
+
+	tests := []struct {
+		name     string
+		query    string
+		dialect  Dialect
+		expected string
+		args     []interface{}
+	}{
+		{
+			name:     "Postgres",
+			query:    "price[gte]=10&status[in]=a,b",
+			dialect:  Postgres,
+			expected: "WHERE p.price >= $1 AND p.status IN ($2,$3)",
+			args:     []interface{}{"10", "a", "b"},
+		},
+		{
+			name:     "MySQL",
+			query:    "name[like]=foo%25&price[neq]=3",
+			dialect:  MySQL,
+			expected: "WHERE p.name LIKE ? AND p.price <> ?",
+			args:     []interface{}{"foo%", "3"},
+		},
+		{
+			name:     "SQLite",
+			query:    "user[age][between]=18,30&status=open",
+			dialect:  SQLite,
+			expected: "WHERE p.status = ?1 AND u.age BETWEEN ?2 AND ?3",
+			args:     []interface{}{"open", "18", "30"},
+		},
+		{
+			name:     "Empty expression",
+			query:    "",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := Build(parse(t, tt.query), nil)
+			assert.NoError(t, err)
+			clause, args, err := RenderSQL(expr, SQLOptions{Dialect: tt.dialect, Columns: columns})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, clause)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+func TestRenderSQLRejectsUnknownFields(t *testing.T) {
+	expr, err := Build(parse(t, "password[eq]=x"), nil)
+	assert.NoError(t, err)
+
+	_, _, err = RenderSQL(expr, SQLOptions{Columns: map[string]string{"price": "price"}})
+	var fieldErr *UnknownFieldError
+	if assert.True(t, errors.As(err, &fieldErr)) {
+		assert.Equal(t, "password", fieldErr.Field)
+	}
+}
@@ -0,0 +1,50 @@
+package goqs
+
+// FlattenStyle selects the key notation used by Flatten.
+type FlattenStyle int
+
+const (
+	// FlattenBrackets writes keys as a[b][0].
+	FlattenBrackets FlattenStyle = iota
+	// FlattenDots writes keys as a.b.0.
+	FlattenDots
+)
+
+// Flatten turns a nested parse result into a flat map of bracket or dot keys
+// to string values. Null values become empty strings and empty objects and
+// arrays are omitted.
+func Flatten(result map[string]interface{}, style FlattenStyle) map[string]string {
+	options := normalizeStringifyOptions(nil)
+	options.AllowDots = style == FlattenDots
+
+	flat := map[string]string{}
+	for _, pair := range appendPairs(nil, "", result, options) {
+		if pair.null {
+			flat[pair.key] = ""
+			continue
+		}
+		flat[pair.key] = pair.values[0]
+	}
+	return flat
+}
+
+// Unflatten rebuilds a nested result from flat keys, applying the Depth,
+// ArrayLimit and AllowDots rules Parse applies to query keys. Keys are
+// processed in key order, with array indices compared as numbers, and values
+// are used as given, without decoding.
+func Unflatten(flat map[string]string, opts *ParseOptions) (result map[string]interface{}, err error) {
+	defer recoverParseError(&result, &err)
+
+	options := normalizeParseOptions(opts)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sortParamKeys(keys, options)
+
+	values := make([]parsedValue, 0, len(keys))
+	for i, k := range keys {
+		values = append(values, parsedValue{index: i, key: k, value: flat[k]})
+	}
+	return buildObject(values, options)
+}
@@ -0,0 +1,76 @@
+package goqs
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	result, err := Parse("user[name]=Alice&user[roles][]=admin&user[roles][]=dev&page=2&empty=", nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"user[name]":     "Alice",
+		"user[roles][0]": "admin",
+		"user[roles][1]": "dev",
+		"page":           "2",
+		"empty":          "",
+	}, Flatten(result, FlattenBrackets))
+
+	assert.Equal(t, map[string]string{
+		"user.name":    "Alice",
+		"user.roles.0": "admin",
+		"user.roles.1": "dev",
+		"page":         "2",
+		"empty":        "",
+	}, Flatten(result, FlattenDots))
+}
+
+func TestUnflatten(t *testing.T) {
+	expected := map[string]interface{}{
+		"user": map[string]interface{}{"name": "Alice", "roles": []interface{}{"admin", "dev"}},
+		"page": "2",
+	}
+
+	res, err := Unflatten(map[string]string{"user[name]": "Alice", "user[roles][0]": "admin", "user[roles][1]": "dev", "page": "2"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+
+	res, err = Unflatten(map[string]string{"user.name": "Alice", "user.roles.0": "admin", "user.roles.1": "dev", "page": "2"}, &ParseOptions{AllowDots: true, Depth: 5, ArrayLimit: 20, ParseArrays: true})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, res)
+
+	res, err = Unflatten(map[string]string{"a[b][c]": "x%20y"}, &ParseOptions{Depth: 1, ArrayLimit: 20, ParseArrays: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": map[string]interface{}{"[c]": "x%20y"}}}, res)
+
+	_, err = Unflatten(map[string]string{"a[b][c]": "x"}, &ParseOptions{Depth: 1, StrictDepth: true})
+	assert.Error(t, err)
+}
+
+func TestFlattenRoundTrip(t *testing.T) {
+	result, err := Parse("a[b][0][c]=1&a[b][1][c]=2&d=3", nil)
+	assert.NoError(t, err)
+
+	for _, style := range []FlattenStyle{FlattenBrackets, FlattenDots} {
+		res, err := Unflatten(Flatten(result, style), &ParseOptions{AllowDots: style == FlattenDots, Depth: 5, ArrayLimit: 20, ParseArrays: true})
+		assert.NoError(t, err)
+		assert.Equal(t, result, res)
+	}
+}
+
+func TestUnflattenArrayOrder(t *testing.T) {
+	items := []interface{}{}
+	for i := 0; i < 12; i++ {
+		items = append(items, strconv.Itoa(i))
+	}
+	result := map[string]interface{}{"a": items, "b": map[string]interface{}{"c": items}}
+
+	for _, style := range []FlattenStyle{FlattenBrackets, FlattenDots} {
+		res, err := Unflatten(Flatten(result, style), &ParseOptions{AllowDots: style == FlattenDots, Depth: 5, ArrayLimit: 20, ParseArrays: true})
+		assert.NoError(t, err)
+		assert.Equal(t, result, res)
+	}
+}
@@ -0,0 +1,168 @@
+package goqs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrPathNotFound is wrapped by PathError when a path holds no value.
+var ErrPathNotFound = errors.New("path not found")
+
+// PathError reports a path of a parse result that cannot be read or
+// converted.
+type PathError struct {
+	Path string
+	Err  error
+}
+
+func (e *PathError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// Get returns the value of a parse result at a dot or bracket path such as
+// "user.address.city" or "items[0][name]". Keys that themselves contain dots
+// or brackets cannot be addressed.
+func Get(result interface{}, path string) (interface{}, bool) {
+	current := result
+	for _, segment := range splitPath(path) {
+		next, ok := child(current, segment)
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
+
+// child returns the value stored under segment in an object or array.
+func child(value interface{}, segment string) (interface{}, bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		c, ok := v[segment]
+		return c, ok
+	case *OrderedMap:
+		return v.Get(segment)
+	case map[int]interface{}:
+		if !isIndex(segment) {
+			return nil, false
+		}
+		c, ok := v[atoi(segment)]
+		return c, ok
+	case []interface{}:
+		if !isIndex(segment) || atoi(segment) >= len(v) {
+			return nil, false
+		}
+		return v[atoi(segment)], true
+	}
+	return nil, false
+}
+
+// scalar returns the text of the scalar at path.
+func scalar(result interface{}, path string) (string, error) {
+	value, ok := Get(result, path)
+	if !ok || value == nil {
+		return "", &PathError{Path: path, Err: ErrPathNotFound}
+	}
+	switch value.(type) {
+	case map[string]interface{}, map[int]interface{}, *OrderedMap, []interface{}:
+		return "", &PathError{Path: path, Err: errors.New("value is not a scalar")}
+	}
+	return AsString(value), nil
+}
+
+// GetString returns the scalar at path as a string.
+func GetString(result interface{}, path string) (string, error) {
+	return scalar(result, path)
+}
+
+// GetInt returns the scalar at path as an int.
+func GetInt(result interface{}, path string) (int, error) {
+	s, err := scalar(result, path)
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, &PathError{Path: path, Err: fmt.Errorf("cannot convert %q to int", s)}
+	}
+	return n, nil
+}
+
+// GetFloat returns the scalar at path as a float64.
+func GetFloat(result interface{}, path string) (float64, error) {
+	s, err := scalar(result, path)
+	if err != nil {
+		return 0, err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, &PathError{Path: path, Err: fmt.Errorf("cannot convert %q to float", s)}
+	}
+	return f, nil
+}
+
+// GetBool returns the scalar at path as a bool, accepting the values
+// strconv.ParseBool does.
+func GetBool(result interface{}, path string) (bool, error) {
+	s, err := scalar(result, path)
+	if err != nil {
+		return false, err
+	}
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return false, &PathError{Path: path, Err: fmt.Errorf("cannot convert %q to bool", s)}
+	}
+	return b, nil
+}
+
+// GetTime returns the scalar at path as a time parsed with layout.
+func GetTime(result interface{}, path string, layout string) (time.Time, error) {
+	if value, ok := Get(result, path); ok {
+		if t, ok := value.(time.Time); ok {
+			return t, nil
+		}
+	}
+	s, err := scalar(result, path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := time.Parse(layout, s)
+	if err != nil {
+		return time.Time{}, &PathError{Path: path, Err: fmt.Errorf("cannot convert %q to time: %w", s, err)}
+	}
+	return t, nil
+}
+
+// GetStrings returns the array at path as strings. A single scalar is
+// returned as a one-element slice.
+func GetStrings(result interface{}, path string) ([]string, error) {
+	value, ok := Get(result, path)
+	if !ok || value == nil {
+		return nil, &PathError{Path: path, Err: ErrPathNotFound}
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		s, err := scalar(result, path)
+		if err != nil {
+			return nil, err
+		}
+		return []string{s}, nil
+	}
+	strs := make([]string, len(items))
+	for i, item := range items {
+		switch item.(type) {
+		case map[string]interface{}, map[int]interface{}, *OrderedMap, []interface{}:
+			return nil, &PathError{Path: path, Err: fmt.Errorf("item %d is not a scalar", i)}
+		case nil:
+		default:
+			strs[i] = AsString(item)
+		}
+	}
+	return strs, nil
+}
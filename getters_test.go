@@ -0,0 +1,87 @@
+package goqs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	result, err := Parse("user[address][city]=Rio&items[0][name]=a&items[1][name]=b", nil)
+	assert.NoError(t, err)
+
+	value, ok := Get(result, "user.address.city")
+	assert.True(t, ok)
+	assert.Equal(t, "Rio", value)
+
+	value, ok = Get(result, "items[1][name]")
+	assert.True(t, ok)
+	assert.Equal(t, "b", value)
+
+	value, ok = Get(result, "items.0.name")
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+
+	_, ok = Get(result, "items[2].name")
+	assert.False(t, ok)
+	_, ok = Get(result, "user.address.city.zip")
+	assert.False(t, ok)
+
+	ordered, err := ParseOrdered("a[b]=1", nil)
+	assert.NoError(t, err)
+	value, ok = Get(ordered, "a.b")
+	assert.True(t, ok)
+	assert.Equal(t, "1", value)
+}
+
+func TestTypedGetters(t *testing.T) {
+	result, err := Parse("page=2&price=9.5&active=true&since=2024-05-01&tags[]=a&tags[]=b&q=x&bad=yes", nil)
+	assert.NoError(t, err)
+
+	page, err := GetInt(result, "page")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page)
+
+	price, err := GetFloat(result, "price")
+	assert.NoError(t, err)
+	assert.Equal(t, 9.5, price)
+
+	active, err := GetBool(result, "active")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	since, err := GetTime(result, "since", "2006-01-02")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), since)
+
+	tags, err := GetStrings(result, "tags")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, tags)
+
+	single, err := GetStrings(result, "q")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x"}, single)
+
+	s, err := GetString(result, "q")
+	assert.NoError(t, err)
+	assert.Equal(t, "x", s)
+}
+
+func TestGetterErrors(t *testing.T) {
+	result, err := Parse("bad=yes&user[name]=a", nil)
+	assert.NoError(t, err)
+
+	_, err = GetInt(result, "missing")
+	assert.True(t, errors.Is(err, ErrPathNotFound))
+
+	_, err = GetBool(result, "bad")
+	assert.EqualError(t, err, `bad: cannot convert "yes" to bool`)
+
+	_, err = GetString(result, "user")
+	var pathErr *PathError
+	if assert.True(t, errors.As(err, &pathErr)) {
+		assert.Equal(t, "user", pathErr.Path)
+	}
+}
@@ -0,0 +1,97 @@
+package goqs
+
+// mergeGrouped merges value stored under chain into target the way Rack
+// groups arrays of objects: a "[]" followed by further keys adds to the last
+// element of the array, and starts a new element only when the last one
+// already holds that key.
+func mergeGrouped(target interface{}, chain []string, value interface{}, options ParseOptions) interface{} {
+	if chain == nil {
+		return target
+	}
+	push := -1
+	for i := 1; i < len(chain)-1; i++ {
+		if chain[i] == "[]" {
+			push = i
+			break
+		}
+	}
+	if push == -1 || !options.ParseArrays {
+		return Merge(target, parseObject(chain, value, options, true), options)
+	}
+
+	prefix, child := chain[:push], chain[push+1:]
+	found, set := lookupChain(target, chainPath(prefix))
+	elements, isArray := found.([]interface{})
+	if n := len(elements); isArray && n > 0 {
+		if last, ok := elements[n-1].(map[string]interface{}); ok && !hasChain(last, chainPath(child)) {
+			elements[n-1] = mergeGrouped(last, child, value, options)
+			return target
+		}
+	}
+
+	element := mergeGrouped(map[string]interface{}{}, child, value, options)
+	if isArray {
+		set(append(elements, element))
+		return target
+	}
+	pushChain := append(append([]string{}, prefix...), "[]")
+	return Merge(target, parseObject(pushChain, element, options, true), options)
+}
+
+// lookupChain follows path through objects and arrays, returning the value
+// found and a function replacing it in its parent.
+func lookupChain(target interface{}, path []string) (interface{}, func(interface{})) {
+	current := target
+	set := func(interface{}) {}
+	for _, segment := range path {
+		switch c := current.(type) {
+		case map[string]interface{}:
+			key := segment
+			current = c[key]
+			set = func(v interface{}) { c[key] = v }
+		case *OrderedMap:
+			key := segment
+			current, _ = c.Get(key)
+			set = func(v interface{}) { c.Set(key, v) }
+		case map[int]interface{}:
+			if !isIndex(segment) {
+				return nil, set
+			}
+			index := atoi(segment)
+			current = c[index]
+			set = func(v interface{}) { c[index] = v }
+		case []interface{}:
+			if !isIndex(segment) {
+				return nil, set
+			}
+			index := atoi(segment)
+			if index >= len(c) {
+				return nil, set
+			}
+			current = c[index]
+			set = func(v interface{}) { c[index] = v }
+		default:
+			return nil, set
+		}
+	}
+	return current, set
+}
+
+// hasChain reports whether m already holds path. Like Rack, paths holding
+// "[]" never count as present, so their values collect in the last element.
+func hasChain(m map[string]interface{}, path []string) bool {
+	var current interface{} = m
+	for _, segment := range path {
+		if segment == "[]" {
+			return false
+		}
+		c, ok := current.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if current, ok = c[segment]; !ok {
+			return false
+		}
+	}
+	return true
+}
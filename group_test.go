@@ -0,0 +1,65 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupArrayObjects(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected map[string]interface{}
+	}{
+		{
+			name:  "New element when a key repeats",
+			query: "items[][name]=a&items[][qty]=1&items[][name]=b&items[][qty]=2",
+			expected: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"name": "a", "qty": "1"},
+				map[string]interface{}{"name": "b", "qty": "2"},
+			}},
+		},
+		{
+			name:  "Nested keys",
+			query: "items[][product][id]=1&items[][qty]=2&items[][product][id]=3",
+			expected: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"product": map[string]interface{}{"id": "1"}, "qty": "2"},
+				map[string]interface{}{"product": map[string]interface{}{"id": "3"}},
+			}},
+		},
+		{
+			name:  "Arrays inside elements",
+			query: "items[][tags][]=x&items[][tags][]=y&items[][name]=a",
+			expected: map[string]interface{}{"items": []interface{}{
+				map[string]interface{}{"tags": []interface{}{"x", "y"}, "name": "a"},
+			}},
+		},
+		{
+			name:  "Nested groups",
+			query: "order[items][][name]=a&order[items][][name]=b&order[id]=7",
+			expected: map[string]interface{}{"order": map[string]interface{}{
+				"id":    "7",
+				"items": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}},
+			}},
+		},
+		{
+			name:     "Plain pushes",
+			query:    "a[]=1&a[]=2",
+			expected: map[string]interface{}{"a": []interface{}{"1", "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, GroupArrayObjects: true})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+
+	profile := RackProfile()
+	res, err := Parse("items[][name]=a&items[][name]=b", &profile.Parse)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}}}, res)
+}
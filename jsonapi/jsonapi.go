@@ -0,0 +1,183 @@
+// Package jsonapi reads and writes the JSON:API query parameter families:
+// fields, include, filter, page and sort.
+package jsonapi
+
+import (
+	"fmt"
+	"strings"
+
+	goqs "github.com/globocom/go-qs"
+)
+
+// Query holds the JSON:API parameters of a request.
+type Query struct {
+	// Fields maps resource types to their sparse fieldsets.
+	Fields map[string][]string
+	// Include lists relationship paths such as "author.posts".
+	Include []string
+	// Filter holds the filter family as parsed by goqs.Parse; JSON:API
+	// leaves its contents to the server.
+	Filter map[string]interface{}
+	// Page holds the page family, e.g. number and size or cursors.
+	Page map[string]string
+	// Sort lists the sort fields in order.
+	Sort []goqs.SortSpec
+	// Extra holds every other parameter.
+	Extra map[string]interface{}
+}
+
+var parseOptions = goqs.ParseOptions{
+	ArrayLimit:        20,
+	Depth:             5,
+	IgnoreQueryPrefix: true,
+	ParameterLimit:    1000,
+	ParseArrays:       true,
+}
+
+// Parse reads the JSON:API parameters of query. Only fields, include and sort
+// are split on commas, as the specification requires; filter and page values
+// are kept as given.
+func Parse(query string) (*Query, error) {
+	result, err := goqs.Parse(query, &parseOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Query{}
+	for key, value := range result {
+		switch key {
+		case "fields":
+			fields, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("jsonapi: fields must be keyed by resource type")
+			}
+			q.Fields = make(map[string][]string, len(fields))
+			for typ, list := range fields {
+				s, ok := list.(string)
+				if !ok {
+					return nil, fmt.Errorf("jsonapi: fields[%s] must be a comma separated list", typ)
+				}
+				q.Fields[typ] = splitList(s)
+			}
+		case "include":
+			s, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("jsonapi: include must be a comma separated list")
+			}
+			q.Include = splitList(s)
+		case "filter":
+			filter, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("jsonapi: filter must be an object")
+			}
+			q.Filter = filter
+		case "page":
+			page, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("jsonapi: page must be an object")
+			}
+			q.Page = make(map[string]string, len(page))
+			for k, v := range page {
+				s, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("jsonapi: page[%s] must be a single value", k)
+				}
+				q.Page[k] = s
+			}
+		case "sort":
+			if _, ok := value.(string); !ok {
+				return nil, fmt.Errorf("jsonapi: sort must be a comma separated list")
+			}
+			specs, err := goqs.ParseSort(result, nil)
+			if err != nil {
+				return nil, err
+			}
+			q.Sort = specs
+		default:
+			if q.Extra == nil {
+				q.Extra = map[string]interface{}{}
+			}
+			q.Extra[key] = value
+		}
+	}
+	return q, nil
+}
+
+// Encode serializes q back into a query string suitable for links, in the
+// order fields, filter, include, page, sort and then any extra parameters.
+func (q *Query) Encode() (string, error) {
+	lists := map[string]interface{}{}
+	if len(q.Fields) > 0 {
+		fields := make(map[string]interface{}, len(q.Fields))
+		for typ, list := range q.Fields {
+			fields[typ] = list
+		}
+		lists["fields"] = fields
+	}
+	if len(q.Include) > 0 {
+		lists["include"] = q.Include
+	}
+	if len(q.Sort) > 0 {
+		sortList := make([]string, len(q.Sort))
+		for i, spec := range q.Sort {
+			sortList[i] = spec.Field
+			if spec.Desc {
+				sortList[i] = "-" + spec.Field
+			}
+		}
+		lists["sort"] = sortList
+	}
+	objects := map[string]interface{}{}
+	if len(q.Filter) > 0 {
+		objects["filter"] = q.Filter
+	}
+	if len(q.Page) > 0 {
+		page := make(map[string]interface{}, len(q.Page))
+		for k, v := range q.Page {
+			page[k] = v
+		}
+		objects["page"] = page
+	}
+
+	parts := []string{}
+	for _, family := range []string{"fields", "filter", "include", "page", "sort"} {
+		var obj map[string]interface{}
+		arrayFormat := goqs.ArrayFormatIndices
+		if value, ok := lists[family]; ok {
+			obj = map[string]interface{}{family: value}
+			arrayFormat = goqs.ArrayFormatComma
+		} else if value, ok := objects[family]; ok {
+			obj = map[string]interface{}{family: value}
+		} else {
+			continue
+		}
+		part, err := goqs.Stringify(obj, &goqs.StringifyOptions{ArrayFormat: arrayFormat, EncodeValuesOnly: true})
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	if len(q.Extra) > 0 {
+		part, err := goqs.Stringify(q.Extra, &goqs.StringifyOptions{EncodeValuesOnly: true})
+		if err != nil {
+			return "", err
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "&"), nil
+}
+
+func splitList(s string) []string {
+	list := []string{}
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
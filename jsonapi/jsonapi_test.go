@@ -0,0 +1,43 @@
+package jsonapi
+
+import (
+	"testing"
+
+	goqs "github.com/globocom/go-qs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	q, err := Parse("?fields[articles]=title,body&fields[people]=name&include=author.posts,comments&filter[tags]=a,b&page[number]=2&page[size]=10&sort=-created,title&lang=en")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"articles": {"title", "body"}, "people": {"name"}}, q.Fields)
+	assert.Equal(t, []string{"author.posts", "comments"}, q.Include)
+	assert.Equal(t, map[string]interface{}{"tags": "a,b"}, q.Filter)
+	assert.Equal(t, map[string]string{"number": "2", "size": "10"}, q.Page)
+	assert.Equal(t, []goqs.SortSpec{{Field: "created", Desc: true}, {Field: "title"}}, q.Sort)
+	assert.Equal(t, map[string]interface{}{"lang": "en"}, q.Extra)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, query := range []string{"fields=title", "include[]=a", "page=2", "sort[title]=asc"} {
+		_, err := Parse(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	q := &Query{
+		Fields:  map[string][]string{"articles": {"title", "body"}},
+		Include: []string{"author.posts"},
+		Filter:  map[string]interface{}{"status": "open now"},
+		Page:    map[string]string{"size": "10"},
+		Sort:    []goqs.SortSpec{{Field: "created", Desc: true}, {Field: "title"}},
+	}
+	query, err := q.Encode()
+	assert.NoError(t, err)
+	assert.Equal(t, "fields[articles]=title,body&filter[status]=open%20now&include=author.posts&page[size]=10&sort=-created,title", query)
+
+	parsed, err := Parse(query)
+	assert.NoError(t, err)
+	assert.Equal(t, q, parsed)
+}
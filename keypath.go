@@ -0,0 +1,166 @@
+package goqs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// splitPath splits a dot or bracket key path such as "filter.price.gte",
+// "a[b][0]" or "tags[]" into segments, using "[]" for array pushes.
+func splitPath(path string) []string {
+	segments := []string{}
+	current := ""
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			if current != "" {
+				segments = append(segments, current)
+			}
+			current = ""
+		case '[':
+			if current != "" {
+				segments = append(segments, current)
+			}
+			current = ""
+			end := i + 1
+			for end < len(path) && path[end] != ']' {
+				end++
+			}
+			segment := path[i+1 : end]
+			if segment == "" {
+				segment = "[]"
+			}
+			segments = append(segments, segment)
+			i = end
+		default:
+			current += string(path[i])
+		}
+	}
+	if current != "" {
+		segments = append(segments, current)
+	}
+	return segments
+}
+
+// matchPath reports whether path matches pattern segment by segment. A "*"
+// pattern segment matches any segment and "[]" matches any array index.
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, segment := range pattern {
+		switch segment {
+		case "*":
+		case "[]":
+			if path[i] != "[]" && !isIndex(path[i]) {
+				return false
+			}
+		default:
+			if segment != path[i] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// wildcards counts the segments of pattern that match more than one key.
+func wildcards(pattern []string) int {
+	n := 0
+	for _, segment := range pattern {
+		if segment == "*" || segment == "[]" {
+			n++
+		}
+	}
+	return n
+}
+
+// keyAllowed reports whether key matches one of the compiled allowed paths.
+// A nil list allows every key. Unknown keys panic with an UnknownKeyError
+// when RejectUnknownKeys is set.
+func keyAllowed(allowed [][]string, key string, options ParseOptions) bool {
+	if allowed == nil || allowedPath(allowed, chainPath(splitKey(key, options))) {
+		return true
+	}
+	if options.RejectUnknownKeys {
+		panic(&UnknownKeyError{Key: key})
+	}
+	return false
+}
+
+// sortParamKeys sorts parameter keys by their key chains, comparing array indices
+// as numbers so that "a[2]" comes before "a[10]" and arrays are rebuilt in
+// index order.
+func sortParamKeys(keys []string, options ParseOptions) {
+	paths := make(map[string][]string, len(keys))
+	for _, k := range keys {
+		paths[k] = chainPath(splitKey(k, options))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if c := comparePaths(paths[keys[i]], paths[keys[j]]); c != 0 {
+			return c < 0
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// comparePaths orders two key paths segment by segment, comparing indices
+// numerically and other segments lexically.
+func comparePaths(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if isIndex(a[i]) && isIndex(b[i]) && atoi(a[i]) != atoi(b[i]) {
+			if atoi(a[i]) < atoi(b[i]) {
+				return -1
+			}
+			return 1
+		}
+		if a[i] < b[i] {
+			return -1
+		}
+		return 1
+	}
+	return len(a) - len(b)
+}
+
+func isIndex(segment string) bool {
+	index, err := strconv.Atoi(segment)
+	return err == nil && index >= 0
+}
+
+func atoi(segment string) int {
+	index, _ := strconv.Atoi(segment)
+	return index
+}
+
+// UnknownKeyError is returned by Parse when RejectUnknownKeys is set and a
+// parameter matches none of the AllowedKeys.
+type UnknownKeyError struct {
+	Key string
+}
+
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("parameter %q is not allowed", e.Key)
+}
+
+// compileKeyPaths splits every allowed key path into segments.
+func compileKeyPaths(paths []string) [][]string {
+	compiled := make([][]string, len(paths))
+	for i, path := range paths {
+		compiled[i] = splitPath(path)
+	}
+	return compiled
+}
+
+// allowedPath reports whether path matches one of the allowed patterns.
+func allowedPath(allowed [][]string, path []string) bool {
+	for _, pattern := range allowed {
+		if matchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
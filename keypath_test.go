@@ -0,0 +1,85 @@
+package goqs
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPath(t *testing.T) {
+	assert.Equal(t, []string{"filter", "price", "gte"}, splitPath("filter.price.gte"))
+	assert.Equal(t, []string{"a", "b", "0", "[]"}, splitPath("a[b][0][]"))
+	assert.Equal(t, []string{"a", "b", "c"}, splitPath("a.b[c]"))
+}
+
+func TestParseAllowedKeys(t *testing.T) {
+	allowed := []string{"page", "filter.*.eq", "items[].name"}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Unknown keys dropped",
+			query:    "page=1&debug=true&x[y][z]=1",
+			expected: map[string]interface{}{"page": "1"},
+		},
+		{
+			name:     "Wildcard keys",
+			query:    "filter[status][eq]=open&filter[status][gt]=1",
+			expected: map[string]interface{}{"filter": map[string]interface{}{"status": map[string]interface{}{"eq": "open"}}},
+		},
+		{
+			name:     "Array index wildcard",
+			query:    "items[0][name]=a&items[1][name]=b&items[1][price]=2",
+			expected: map[string]interface{}{"items": []interface{}{map[string]interface{}{"name": "a"}, map[string]interface{}{"name": "b"}}},
+		},
+		{
+			name:     "Keys deeper than allowed are dropped",
+			query:    "page[a]=1",
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, AllowedKeys: allowed})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestParseRejectUnknownKeys(t *testing.T) {
+	res, err := Parse("page=1&debug=true", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, AllowedKeys: []string{"page"}, RejectUnknownKeys: true})
+	assert.Nil(t, res)
+
+	var unknownErr *UnknownKeyError
+	if assert.True(t, errors.As(err, &unknownErr)) {
+		assert.Equal(t, "debug", unknownErr.Key)
+	}
+}
+
+func TestAllowedKeysSkipValueDecoder(t *testing.T) {
+	var decoded []string
+	decoder := func(str string, decode DecodeFunc, charset string, path []string) (interface{}, error) {
+		decoded = append(decoded, path[0])
+		return strconv.Atoi(decode(str))
+	}
+	options := &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, AllowedKeys: []string{"page"}, ValueDecoder: decoder}
+
+	res, err := Parse("page=1&junk=x", options)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"page": 1}, res)
+	assert.Equal(t, []string{"page"}, decoded)
+
+	decoded = nil
+	res, err = FromValues(url.Values{"page": {"2"}, "junk": {"x"}}, options)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"page": 2}, res)
+	assert.Equal(t, []string{"page"}, decoded)
+}
@@ -0,0 +1,111 @@
+package goqs
+
+// MergePolicy decides how MergeQueries resolves a key present in both
+// queries. Objects present in both are always merged key by key.
+type MergePolicy int
+
+const (
+	// OverrideWins replaces base values with override values.
+	OverrideWins MergePolicy = iota
+	// BaseWins keeps base values, only adding keys missing from base.
+	BaseWins
+	// AppendArrays concatenates arrays, treating a scalar facing an array as
+	// a one-element array; other values follow OverrideWins.
+	AppendArrays
+)
+
+// MergeQueries deep-merges two parse results, e.g. a saved filter preset
+// under user-supplied parameters. Unlike Merge it never modifies its
+// arguments and the result shares no maps or slices with them.
+func MergeQueries(base, override map[string]interface{}, policy MergePolicy) map[string]interface{} {
+	result := cloneValue(base).(map[string]interface{})
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+	for k, v := range override {
+		if existing, ok := result[k]; ok {
+			result[k] = mergeValues(existing, cloneValue(v), policy)
+		} else {
+			result[k] = cloneValue(v)
+		}
+	}
+	return result
+}
+
+// mergeValues merges two values that are not shared with the caller.
+func mergeValues(base, override interface{}, policy MergePolicy) interface{} {
+	baseMap, baseIsMap := base.(map[string]interface{})
+	overrideMap, overrideIsMap := override.(map[string]interface{})
+	if baseIsMap && overrideIsMap {
+		for k, v := range overrideMap {
+			if existing, ok := baseMap[k]; ok {
+				baseMap[k] = mergeValues(existing, v, policy)
+			} else {
+				baseMap[k] = v
+			}
+		}
+		return baseMap
+	}
+
+	switch policy {
+	case BaseWins:
+		return base
+	case AppendArrays:
+		baseItems, baseIsArray := base.([]interface{})
+		overrideItems, overrideIsArray := override.([]interface{})
+		switch {
+		case baseIsArray && overrideIsArray:
+			return append(baseItems, overrideItems...)
+		case baseIsArray && !overrideIsMap:
+			return append(baseItems, override)
+		case overrideIsArray && !baseIsMap:
+			return append([]interface{}{base}, overrideItems...)
+		}
+	}
+	return override
+}
+
+// cloneValue deep-copies the objects and arrays of a parse result.
+func cloneValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			m[k] = cloneValue(item)
+		}
+		return m
+	case map[int]interface{}:
+		if v == nil {
+			return v
+		}
+		m := make(map[int]interface{}, len(v))
+		for k, item := range v {
+			m[k] = cloneValue(item)
+		}
+		return m
+	case *OrderedMap:
+		if v == nil {
+			return v
+		}
+		m := NewOrderedMap()
+		for _, k := range v.keys {
+			m.Set(k, cloneValue(v.values[k]))
+		}
+		return m
+	case []interface{}:
+		if v == nil {
+			return v
+		}
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = cloneValue(item)
+		}
+		return items
+	case []string:
+		return append([]string(nil), v...)
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeQueries(t *testing.T) {
+	parse := func(query string) map[string]interface{} {
+		result, err := Parse(query, nil)
+		assert.NoError(t, err)
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		policy   MergePolicy
+		expected map[string]interface{}
+	}{
+		{
+			name:   "Override wins",
+			policy: OverrideWins,
+			expected: map[string]interface{}{
+				"filter": map[string]interface{}{"status": "closed", "owner": "me", "tags": []interface{}{"c"}},
+				"page":   "1",
+				"sort":   "name",
+			},
+		},
+		{
+			name:   "Base wins",
+			policy: BaseWins,
+			expected: map[string]interface{}{
+				"filter": map[string]interface{}{"status": "open", "owner": "me", "tags": []interface{}{"a", "b"}},
+				"page":   "1",
+				"sort":   "name",
+			},
+		},
+		{
+			name:   "Append arrays",
+			policy: AppendArrays,
+			expected: map[string]interface{}{
+				"filter": map[string]interface{}{"status": "closed", "owner": "me", "tags": []interface{}{"a", "b", "c"}},
+				"page":   "1",
+				"sort":   "name",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := parse("filter[status]=open&filter[owner]=me&filter[tags][]=a&filter[tags][]=b&page=1")
+			override := parse("filter[status]=closed&filter[tags][]=c&sort=name")
+
+			result := MergeQueries(base, override, tt.policy)
+			assert.Equal(t, tt.expected, result)
+
+			assert.Equal(t, parse("filter[status]=open&filter[owner]=me&filter[tags][]=a&filter[tags][]=b&page=1"), base)
+			assert.Equal(t, parse("filter[status]=closed&filter[tags][]=c&sort=name"), override)
+
+			Set(result, "filter[tags][0]", "changed")
+			assert.Equal(t, parse("filter[status]=closed&filter[tags][]=c&sort=name"), override)
+		})
+	}
+}
+
+func TestMergeQueriesNil(t *testing.T) {
+	assert.Equal(t, map[string]interface{}{"a": "1"}, MergeQueries(nil, map[string]interface{}{"a": "1"}, OverrideWins))
+}
@@ -0,0 +1,188 @@
+package goqs
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Set stores value at a dot or bracket path of a parse result, such as
+// "filter[status][in]", creating intermediate objects and arrays the way
+// Parse does: numeric keys up to the default ArrayLimit and "[]" create
+// arrays, other keys create objects. A "[]" segment appends to an array.
+func Set(result interface{}, path string, value interface{}) error {
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		return &PathError{Path: path, Err: errors.New("empty path")}
+	}
+	if !isObject(result) {
+		return &PathError{Path: path, Err: errors.New("result must be an object")}
+	}
+	if _, err := setIn(result, segments, normalizeValue(value)); err != nil {
+		return &PathError{Path: path, Err: err}
+	}
+	return nil
+}
+
+// Append adds value to the array at path, creating the array when path is
+// missing. A scalar already stored at path is combined with value into an
+// array, as Parse does for repeated keys.
+func Append(result interface{}, path string, value interface{}) error {
+	existing, ok := Get(result, path)
+	switch existing.(type) {
+	case []interface{}:
+		return Set(result, path+"[]", value)
+	case map[string]interface{}, map[int]interface{}, *OrderedMap:
+		return &PathError{Path: path, Err: errors.New("cannot append to an object")}
+	}
+	if !ok || existing == nil {
+		return Set(result, path, []interface{}{normalizeValue(value)})
+	}
+	return Set(result, path, []interface{}{existing, normalizeValue(value)})
+}
+
+// Delete removes the value at path, reporting whether it existed. Deleting
+// an array item shifts the following items down.
+func Delete(result interface{}, path string) bool {
+	return deleteIn(result, splitPath(path))
+}
+
+// deleteIn removes the value at the given path segments.
+func deleteIn(result interface{}, segments []string) bool {
+	if len(segments) == 0 {
+		return false
+	}
+	last := segments[len(segments)-1]
+	parent, set := lookupChain(result, segments[:len(segments)-1])
+
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := p[last]; !ok {
+			return false
+		}
+		delete(p, last)
+		return true
+	case *OrderedMap:
+		if _, ok := p.Get(last); !ok {
+			return false
+		}
+		p.Delete(last)
+		return true
+	case map[int]interface{}:
+		if !isIndex(last) {
+			return false
+		}
+		if _, ok := p[atoi(last)]; !ok {
+			return false
+		}
+		delete(p, atoi(last))
+		return true
+	case []interface{}:
+		if !isIndex(last) || atoi(last) >= len(p) {
+			return false
+		}
+		index := atoi(last)
+		set(append(p[:index:index], p[index+1:]...))
+		return true
+	}
+	return false
+}
+
+// setIn stores value under segments inside container, returning the
+// container, which is a new slice when an array grew.
+func setIn(container interface{}, segments []string, value interface{}) (interface{}, error) {
+	segment, rest := segments[0], segments[1:]
+
+	var existing interface{}
+	var put func(interface{}) (interface{}, error)
+	switch c := container.(type) {
+	case map[string]interface{}:
+		if segment == "[]" {
+			return nil, errors.New("cannot push to an object")
+		}
+		existing = c[segment]
+		put = func(v interface{}) (interface{}, error) {
+			c[segment] = v
+			return c, nil
+		}
+	case *OrderedMap:
+		if segment == "[]" {
+			return nil, errors.New("cannot push to an object")
+		}
+		existing, _ = c.Get(segment)
+		put = func(v interface{}) (interface{}, error) {
+			c.Set(segment, v)
+			return c, nil
+		}
+	case map[int]interface{}:
+		if !isIndex(segment) {
+			return nil, fmt.Errorf("invalid index %q", segment)
+		}
+		existing = c[atoi(segment)]
+		put = func(v interface{}) (interface{}, error) {
+			c[atoi(segment)] = v
+			return c, nil
+		}
+	case []interface{}:
+		index := len(c)
+		if segment != "[]" {
+			if !isIndex(segment) {
+				return nil, fmt.Errorf("invalid index %q", segment)
+			}
+			index = atoi(segment)
+		}
+		if index > len(c) {
+			return nil, fmt.Errorf("index %d out of range", index)
+		}
+		if index < len(c) {
+			existing = c[index]
+		}
+		put = func(v interface{}) (interface{}, error) {
+			if index == len(c) {
+				return append(c, v), nil
+			}
+			c[index] = v
+			return c, nil
+		}
+	default:
+		return nil, fmt.Errorf("cannot set %q inside a scalar value", segment)
+	}
+
+	if len(rest) == 0 {
+		return put(value)
+	}
+	if existing == nil {
+		existing = newContainer(rest[0])
+	}
+	updated, err := setIn(existing, rest, value)
+	if err != nil {
+		return nil, err
+	}
+	return put(updated)
+}
+
+func newContainer(segment string) interface{} {
+	if segment == "[]" || (isIndex(segment) && atoi(segment) <= defaults.ArrayLimit) {
+		return []interface{}{}
+	}
+	return map[string]interface{}{}
+}
+
+// normalizeValue converts common Go collections into the types Parse
+// produces.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items
+	case map[string]string:
+		m := make(map[string]interface{}, len(v))
+		for k, s := range v {
+			m[k] = s
+		}
+		return m
+	}
+	return value
+}
@@ -0,0 +1,110 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		path     string
+		value    interface{}
+		expected string
+	}{
+		{
+			name:     "Nested filter",
+			query:    "page=1",
+			path:     "filter[status][in]",
+			value:    []string{"a", "b"},
+			expected: "filter[status][in][0]=a&filter[status][in][1]=b&page=1",
+		},
+		{
+			name:     "Replace value",
+			query:    "page=1&q=x",
+			path:     "page",
+			value:    "2",
+			expected: "page=2&q=x",
+		},
+		{
+			name:     "Push to array",
+			query:    "tags[]=a",
+			path:     "tags[]",
+			value:    "b",
+			expected: "tags[0]=a&tags[1]=b",
+		},
+		{
+			name:     "Create array by index",
+			query:    "",
+			path:     "items[0].name",
+			value:    "x",
+			expected: "items[0][name]=x",
+		},
+		{
+			name:     "Large indices create objects",
+			query:    "",
+			path:     "a[100]",
+			value:    "x",
+			expected: "a[100]=x",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.query, nil)
+			assert.NoError(t, err)
+			assert.NoError(t, Set(result, tt.path, tt.value))
+			query, err := Stringify(result, &StringifyOptions{SkipEncode: true})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestSetErrors(t *testing.T) {
+	result, err := Parse("q=x&tags[]=a", nil)
+	assert.NoError(t, err)
+
+	assert.Error(t, Set(result, "q[a]", "1"))
+	assert.Error(t, Set(result, "tags[5]", "b"))
+	assert.Error(t, Set(result, "", "b"))
+	assert.Error(t, Set("q", "a", "b"))
+}
+
+func TestAppend(t *testing.T) {
+	result, err := Parse("tags[]=a&q=x", nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, Append(result, "tags", "b"))
+	assert.NoError(t, Append(result, "q", "y"))
+	assert.NoError(t, Append(result, "filter[ids]", "1"))
+	assert.Error(t, Append(result, "filter", "1"))
+
+	assert.Equal(t, map[string]interface{}{
+		"tags":   []interface{}{"a", "b"},
+		"q":      []interface{}{"x", "y"},
+		"filter": map[string]interface{}{"ids": []interface{}{"1"}},
+	}, result)
+}
+
+func TestDelete(t *testing.T) {
+	result, err := Parse("utm_source=x&page=2&tags[]=a&tags[]=b&tags[]=c&filter[a]=1", nil)
+	assert.NoError(t, err)
+
+	assert.True(t, Delete(result, "utm_source"))
+	assert.True(t, Delete(result, "tags[1]"))
+	assert.True(t, Delete(result, "filter.a"))
+	assert.False(t, Delete(result, "missing"))
+	assert.False(t, Delete(result, "tags[5]"))
+
+	query, err := Stringify(result, &StringifyOptions{SkipEncode: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "page=2&tags[0]=a&tags[1]=c", query)
+
+	ordered, err := ParseOrdered("b=1&a=2", nil)
+	assert.NoError(t, err)
+	assert.True(t, Delete(ordered, "b"))
+	assert.Equal(t, []string{"a"}, ordered.Keys())
+}
@@ -0,0 +1,176 @@
+// Package openapi describes the query parameters goqs.Parse accepts for a
+// struct as OpenAPI 3 parameter objects.
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	goqs "github.com/globocom/go-qs"
+)
+
+// Parameter is an OpenAPI 3 parameter object.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Style    string  `json:"style,omitempty"`
+	Explode  bool    `json:"explode"`
+	Schema   *Schema `json:"schema"`
+}
+
+// Schema is the subset of the OpenAPI 3 schema object used for parameters.
+type Schema struct {
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Parameters describes the exported fields of the struct v as query
+// parameters. Field names come from `qs:"name"` tags, falling back to the Go
+// field name; `qs:"-"` skips a field and `qs:"name,required"` marks it
+// required. Nested structs and maps become deepObject parameters. Array
+// sizes are limited by opts.ArrayLimit, and nesting deeper than opts.Depth,
+// which Parse would not expand, is reported as an error.
+func Parameters(v interface{}, opts *goqs.ParseOptions) ([]Parameter, error) {
+	options := goqs.ParseOptions{ArrayLimit: 20, Depth: 5, ParseArrays: true}
+	if opts != nil {
+		options = *opts
+	}
+
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("openapi: expected a struct, got %v", t)
+	}
+
+	params := []Parameter{}
+	for _, field := range fields(t) {
+		schema, err := schemaFor(field.typ, field.name, 0, options)
+		if err != nil {
+			return nil, err
+		}
+		param := Parameter{Name: field.name, In: "query", Required: field.required, Style: string(goqs.StyleForm), Explode: true, Schema: schema}
+		if schema.Type == "object" {
+			param.Style = string(goqs.StyleDeepObject)
+		}
+		if style, ok := options.Styles[field.name]; ok {
+			param.Style = string(style.Style)
+			param.Explode = style.Explode
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+type structField struct {
+	name     string
+	typ      reflect.Type
+	required bool
+}
+
+func fields(t reflect.Type) []structField {
+	result := []structField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := f.Name
+		required := false
+		if tag, ok := f.Tag.Lookup("qs"); ok {
+			parts := strings.Split(tag, ",")
+			if parts[0] == "-" {
+				continue
+			}
+			if parts[0] != "" {
+				name = parts[0]
+			}
+			for _, opt := range parts[1:] {
+				if opt == "required" {
+					required = true
+				}
+			}
+		}
+		result = append(result, structField{name: name, typ: f.Type, required: required})
+	}
+	return result
+}
+
+// schemaFor describes t found at path, depth bracket segments below the
+// parameter name.
+func schemaFor(t reflect.Type, path string, depth int, options goqs.ParseOptions) (*Schema, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+	case reflect.Slice, reflect.Array:
+		if !options.ParseArrays {
+			return nil, fmt.Errorf("openapi: %s is an array but ParseArrays is disabled", path)
+		}
+		items, err := nested(t.Elem(), path+"[]", depth, options)
+		if err != nil {
+			return nil, err
+		}
+		maxItems := options.ArrayLimit
+		return &Schema{Type: "array", Items: items, MaxItems: &maxItems}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("openapi: %s must have string keys", path)
+		}
+		values, err := nested(t.Elem(), path+"[*]", depth, options)
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for _, field := range fields(t) {
+			property, err := nested(field.typ, path+"["+field.name+"]", depth, options)
+			if err != nil {
+				return nil, err
+			}
+			schema.Properties[field.name] = property
+			if field.required {
+				schema.Required = append(schema.Required, field.name)
+			}
+		}
+		return schema, nil
+	}
+	return nil, fmt.Errorf("openapi: unsupported type %s for %s", t, path)
+}
+
+// nested describes a value one bracket segment deeper, failing once Parse
+// would stop splitting the key.
+func nested(t reflect.Type, path string, depth int, options goqs.ParseOptions) (*Schema, error) {
+	if depth+1 > options.Depth {
+		return nil, fmt.Errorf("openapi: %s exceeds the depth limit of %d", path, options.Depth)
+	}
+	return schemaFor(t, path, depth+1, options)
+}
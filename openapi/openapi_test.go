@@ -0,0 +1,70 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	goqs "github.com/globocom/go-qs"
+	"github.com/stretchr/testify/assert"
+)
+
+type listParams struct {
+	Query  string            `qs:"q,required"`
+	Page   int               `qs:"page"`
+	Tags   []string          `qs:"tags"`
+	Since  time.Time         `qs:"since"`
+	Filter filterParams      `qs:"filter"`
+	Labels map[string]string `qs:"labels"`
+	Secret string            `qs:"-"`
+}
+
+type filterParams struct {
+	Price priceRange `qs:"price"`
+	Open  bool       `qs:"open"`
+}
+
+type priceRange struct {
+	Gte float64 `qs:"gte"`
+	Lt  float64 `qs:"lt"`
+}
+
+func TestParameters(t *testing.T) {
+	params, err := Parameters(listParams{}, &goqs.ParseOptions{Depth: 5, ArrayLimit: 10, ParseArrays: true})
+	assert.NoError(t, err)
+
+	maxItems := 10
+	assert.Equal(t, []Parameter{
+		{Name: "q", In: "query", Required: true, Style: "form", Explode: true, Schema: &Schema{Type: "string"}},
+		{Name: "page", In: "query", Style: "form", Explode: true, Schema: &Schema{Type: "integer", Format: "int32"}},
+		{Name: "tags", In: "query", Style: "form", Explode: true, Schema: &Schema{Type: "array", Items: &Schema{Type: "string"}, MaxItems: &maxItems}},
+		{Name: "since", In: "query", Style: "form", Explode: true, Schema: &Schema{Type: "string", Format: "date-time"}},
+		{Name: "filter", In: "query", Style: "deepObject", Explode: true, Schema: &Schema{Type: "object", Properties: map[string]*Schema{
+			"price": {Type: "object", Properties: map[string]*Schema{
+				"gte": {Type: "number", Format: "double"},
+				"lt":  {Type: "number", Format: "double"},
+			}},
+			"open": {Type: "boolean"},
+		}}},
+		{Name: "labels", In: "query", Style: "deepObject", Explode: true, Schema: &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}},
+	}, params)
+}
+
+func TestParametersLimits(t *testing.T) {
+	_, err := Parameters(&listParams{}, &goqs.ParseOptions{Depth: 1, ArrayLimit: 10, ParseArrays: true})
+	assert.EqualError(t, err, "openapi: filter[price][gte] exceeds the depth limit of 1")
+
+	_, err = Parameters(listParams{}, &goqs.ParseOptions{Depth: 5, ParseArrays: false})
+	assert.Error(t, err)
+
+	_, err = Parameters("q", nil)
+	assert.Error(t, err)
+}
+
+func TestParametersStyles(t *testing.T) {
+	params, err := Parameters(struct {
+		IDs []int `qs:"ids"`
+	}{}, &goqs.ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, Styles: map[string]goqs.ParamStyle{"ids": {Style: goqs.StylePipeDelimited}}})
+	assert.NoError(t, err)
+	assert.Equal(t, "pipeDelimited", params[0].Style)
+	assert.False(t, params[0].Explode)
+}
@@ -0,0 +1,224 @@
+package goqs
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// OrderedMap is a string keyed map that remembers the order in which keys
+// were first set. Merge, Compact and Stringify preserve that order.
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// NewOrderedMap returns an empty OrderedMap.
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: map[string]interface{}{}}
+}
+
+// Get returns the value stored under key.
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// Set stores value under key, appending key if it is new.
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Delete removes key.
+func (m *OrderedMap) Delete(key string) {
+	if _, exists := m.values[key]; !exists {
+		return
+	}
+	delete(m.values, key)
+	for i, k := range m.keys {
+		if k == key {
+			m.keys = append(m.keys[:i], m.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Keys returns the keys in order.
+func (m *OrderedMap) Keys() []string {
+	return append([]string{}, m.keys...)
+}
+
+// Len returns the number of keys.
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// ToMap returns the entries as a plain map, converting nested ordered maps.
+func (m *OrderedMap) ToMap() map[string]interface{} {
+	result := make(map[string]interface{}, len(m.keys))
+	for _, k := range m.keys {
+		result[k] = unorder(m.values[k])
+	}
+	return result
+}
+
+func unorder(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *OrderedMap:
+		return v.ToMap()
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = unorder(item)
+		}
+		return items
+	}
+	return value
+}
+
+// MarshalJSON encodes the map as a JSON object in key order.
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// merge merges source into m, following the rules Merge applies to maps.
+func (m *OrderedMap) merge(source interface{}, options ParseOptions) *OrderedMap {
+	var keys []string
+	var values map[string]interface{}
+	switch s := source.(type) {
+	case *OrderedMap:
+		keys, values = s.keys, s.values
+	case map[string]interface{}:
+		for k := range s {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values = s
+	case []interface{}, map[int]interface{}:
+		return m
+	default:
+		if options.PlainObjects || options.AllowPrototypes {
+			m.Set(AsString(source), true)
+		}
+		return m
+	}
+
+	for _, k := range keys {
+		value := values[k]
+		existing, exists := m.values[k]
+		switch {
+		case !exists:
+			if value != nil || keepsNulls(options) {
+				m.Set(k, value)
+			}
+		case value != nil:
+			m.values[k] = Merge(existing, value, options)
+		}
+	}
+	return m
+}
+
+// ParseOrdered parses str like Parse, returning objects as ordered maps whose
+// keys follow the order in which they first appear in the query.
+func ParseOrdered(str string, opts *ParseOptions) (ordered *OrderedMap, err error) {
+	var result map[string]interface{}
+	defer recoverParseError(&result, &err)
+
+	options := normalizeParseOptions(opts)
+	values := parseQuery(str, options)
+	result, err = buildObject(values, options)
+	if err != nil {
+		return nil, err
+	}
+	ranks := map[string]int{}
+	for _, value := range values {
+		path := orderPath(chainPath(splitKey(value.key, options)))
+		for i := 1; i <= len(path); i++ {
+			prefix := strings.Join(path[:i], "\x00")
+			if _, seen := ranks[prefix]; !seen {
+				ranks[prefix] = len(ranks)
+			}
+		}
+	}
+	return toOrdered(result, nil, ranks).(*OrderedMap), nil
+}
+
+// orderPath replaces array indices in path so that the elements of an array
+// share the key order of their first occurrence.
+func orderPath(path []string) []string {
+	normalized := make([]string, len(path))
+	for i, segment := range path {
+		if isIndex(segment) {
+			segment = "[]"
+		}
+		normalized[i] = segment
+	}
+	return normalized
+}
+
+// toOrdered converts the maps in value, found at path, into ordered maps.
+// Keys missing from ranks, such as those added by post-processing, follow
+// the ranked keys in lexical order.
+func toOrdered(value interface{}, path []string, ranks map[string]int) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		rank := func(k string) int {
+			childPath := orderPath(append(append([]string{}, path...), k))
+			if r, ok := ranks[strings.Join(childPath, "\x00")]; ok {
+				return r
+			}
+			return len(ranks)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			ri, rj := rank(keys[i]), rank(keys[j])
+			if ri != rj {
+				return ri < rj
+			}
+			return keys[i] < keys[j]
+		})
+		m := NewOrderedMap()
+		for _, k := range keys {
+			m.Set(k, toOrdered(v[k], append(append([]string{}, path...), k), ranks))
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = toOrdered(item, append(append([]string{}, path...), "[]"), ranks)
+		}
+		return items
+	case map[int]interface{}:
+		for i, item := range v {
+			v[i] = toOrdered(item, append(append([]string{}, path...), "[]"), ranks)
+		}
+		return v
+	}
+	return value
+}
@@ -0,0 +1,89 @@
+package goqs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrdered(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "Top level order",
+			query:    "z=1&a=2&m=3",
+			expected: `{"z":"1","a":"2","m":"3"}`,
+		},
+		{
+			name:     "Nested order",
+			query:    "user[name]=Alice&page=2&user[age]=30&user[email]=a@b.c",
+			expected: `{"user":{"name":"Alice","age":"30","email":"a@b.c"},"page":"2"}`,
+		},
+		{
+			name:     "Objects inside arrays",
+			query:    "items[0][qty]=1&items[0][id]=a&items[1][id]=b&items[1][qty]=2",
+			expected: `{"items":[{"qty":"1","id":"a"},{"qty":"2","id":"b"}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ParseOrdered(tt.query, nil)
+			assert.NoError(t, err)
+			data, err := json.Marshal(res)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, string(data))
+		})
+	}
+}
+
+func TestOrderedMapMergeAndStringify(t *testing.T) {
+	base, err := ParseOrdered("b=1&a[y]=2", nil)
+	assert.NoError(t, err)
+	extra, err := ParseOrdered("a[x]=3&c=4&b=5", nil)
+	assert.NoError(t, err)
+
+	merged := Merge(base, extra, normalizeParseOptions(nil)).(*OrderedMap)
+	assert.Equal(t, []string{"b", "a", "c"}, merged.Keys())
+
+	query, err := Stringify(Compact(merged), &StringifyOptions{SkipEncode: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "b[0]=1&b[1]=5&a[y]=2&a[x]=3&c=4", query)
+
+	assert.Equal(t, map[string]interface{}{
+		"b": []interface{}{"1", "5"},
+		"a": map[string]interface{}{"y": "2", "x": "3"},
+		"c": "4",
+	}, merged.ToMap())
+
+	merged.Delete("b")
+	assert.Equal(t, []string{"a", "c"}, merged.Keys())
+	assert.Equal(t, 2, merged.Len())
+}
+
+func TestParseOrderedDecodesOnce(t *testing.T) {
+	calls := 0
+	decoder := func(str string, decode DecodeFunc, charset string, path []string) (interface{}, error) {
+		calls++
+		return decode(str), nil
+	}
+
+	res, err := ParseOrdered("b=1&a[x]=2&a[y]=3", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, ValueDecoder: decoder})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, res.Keys())
+	assert.Equal(t, 3, calls)
+}
+
+func TestParseOrderedDecoderPanic(t *testing.T) {
+	decoder := func(s string, decode DecodeFunc, charset string, typ string) string {
+		panic("bad value")
+	}
+
+	res, err := ParseOrdered("a=1", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, Decoder: decoder})
+	assert.EqualError(t, err, "bad value")
+	assert.Nil(t, res)
+}
@@ -0,0 +1,207 @@
+package goqs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SortSpec is a single sort key of a query.
+type SortSpec struct {
+	Field string
+	Desc  bool
+}
+
+// SortOptions configures ParseSort.
+type SortOptions struct {
+	// Param is the sort parameter name, "sort" if empty.
+	Param string
+	// Allowed lists the sortable fields. A nil list allows every field.
+	Allowed []string
+}
+
+// Page holds offset or cursor based pagination parameters.
+type Page struct {
+	Number int
+	Size   int
+	After  string
+	Before string
+}
+
+// PageOptions configures ParsePage.
+type PageOptions struct {
+	// Param is the pagination parameter name, "page" if empty.
+	Param string
+	// DefaultSize is used when no size is given.
+	DefaultSize int
+	// MaxSize clamps the page size when positive, and is the size used when
+	// neither a size nor DefaultSize is given.
+	MaxSize int
+}
+
+// ParamError reports an invalid sort or pagination parameter.
+type ParamError struct {
+	Param  string
+	Value  string
+	Reason string
+}
+
+func (e *ParamError) Error() string {
+	return fmt.Sprintf("invalid %s parameter %q: %s", e.Param, e.Value, e.Reason)
+}
+
+// ParseSort reads the sort keys of a Parse or ParseOrdered result. It accepts
+// comma separated fields with a "-" prefix for descending order
+// (sort=-created_at,name), repeated or bracketed lists of them, and field maps
+// such as sort[created_at]=desc. Field maps keep their query order when read
+// from a ParseOrdered result; a plain map with several fields has lost it and
+// is rejected.
+func ParseSort(result interface{}, opts *SortOptions) ([]SortSpec, error) {
+	param := "sort"
+	var allowed []string
+	if opts != nil {
+		if opts.Param != "" {
+			param = opts.Param
+		}
+		allowed = opts.Allowed
+	}
+
+	var value interface{}
+	switch r := result.(type) {
+	case map[string]interface{}:
+		value = r[param]
+	case *OrderedMap:
+		value, _ = r.Get(param)
+	}
+
+	var specs []SortSpec
+	var err error
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		specs = parseSortList(v)
+	case []interface{}:
+		for _, item := range v {
+			specs = append(specs, parseSortList(AsString(item))...)
+		}
+	case *OrderedMap:
+		specs, err = parseSortFields(param, v.Keys(), func(field string) interface{} {
+			direction, _ := v.Get(field)
+			return direction
+		})
+	case map[string]interface{}:
+		fields := make([]string, 0, len(v))
+		for field := range v {
+			fields = append(fields, field)
+		}
+		if len(fields) > 1 {
+			sort.Strings(fields)
+			return nil, &ParamError{Param: param, Value: strings.Join(fields, ","), Reason: "field order is lost, use ParseOrdered"}
+		}
+		specs, err = parseSortFields(param, fields, func(field string) interface{} { return v[field] })
+	default:
+		return nil, &ParamError{Param: param, Value: AsString(v), Reason: "unsupported value"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range specs {
+		if allowed != nil && !containsString(allowed, spec.Field) {
+			return nil, &ParamError{Param: param, Value: spec.Field, Reason: "field is not sortable"}
+		}
+	}
+	return specs, nil
+}
+
+// parseSortFields reads a field map such as sort[created_at]=desc in the
+// order of fields.
+func parseSortFields(param string, fields []string, get func(string) interface{}) ([]SortSpec, error) {
+	specs := make([]SortSpec, 0, len(fields))
+	for _, field := range fields {
+		direction := strings.ToLower(AsString(get(field)))
+		if direction != "asc" && direction != "desc" {
+			return nil, &ParamError{Param: param, Value: direction, Reason: "direction must be asc or desc"}
+		}
+		specs = append(specs, SortSpec{Field: field, Desc: direction == "desc"})
+	}
+	return specs, nil
+}
+
+func parseSortList(list string) []SortSpec {
+	var specs []SortSpec
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		desc := strings.HasPrefix(field, "-")
+		field = strings.TrimPrefix(field, "-")
+		if field != "" {
+			specs = append(specs, SortSpec{Field: field, Desc: desc})
+		}
+	}
+	return specs
+}
+
+// ParsePage reads page[number], page[size], page[after] and page[before] from
+// a Parse result. A plain page=2 sets the page number. Number defaults to 1
+// and Size to DefaultSize, clamped to MaxSize; Size is 0 only when neither
+// DefaultSize nor MaxSize is set.
+func ParsePage(result map[string]interface{}, opts *PageOptions) (Page, error) {
+	var options PageOptions
+	if opts != nil {
+		options = *opts
+	}
+	param := options.Param
+	if param == "" {
+		param = "page"
+	}
+
+	page := Page{Number: 1, Size: options.DefaultSize}
+	switch v := result[param].(type) {
+	case nil:
+	case string:
+		number, err := parsePageInt(param, v)
+		if err != nil {
+			return Page{}, err
+		}
+		page.Number = number
+	case map[string]interface{}:
+		if number, ok := v["number"]; ok {
+			n, err := parsePageInt(param+"[number]", AsString(number))
+			if err != nil {
+				return Page{}, err
+			}
+			page.Number = n
+		}
+		if size, ok := v["size"]; ok {
+			n, err := parsePageInt(param+"[size]", AsString(size))
+			if err != nil {
+				return Page{}, err
+			}
+			page.Size = n
+		}
+		if after, ok := v["after"]; ok {
+			page.After = AsString(after)
+		}
+		if before, ok := v["before"]; ok {
+			page.Before = AsString(before)
+		}
+	default:
+		return Page{}, &ParamError{Param: param, Value: AsString(v), Reason: "unsupported value"}
+	}
+
+	// An unset size would mean unbounded
+	if options.MaxSize > 0 && (page.Size == 0 || page.Size > options.MaxSize) {
+		page.Size = options.MaxSize
+	}
+	return page, nil
+}
+
+func parsePageInt(param, value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, &ParamError{Param: param, Value: value, Reason: "must be a positive integer"}
+	}
+	return n, nil
+}
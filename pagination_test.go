@@ -0,0 +1,159 @@
+package goqs
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected []SortSpec
+	}{
+		{
+			name:     "Comma separated",
+			query:    "sort=-created_at,name",
+			expected: []SortSpec{{Field: "created_at", Desc: true}, {Field: "name"}},
+		},
+		{
+			name:     "Repeated",
+			query:    "sort[]=name&sort[]=-created_at",
+			expected: []SortSpec{{Field: "name"}, {Field: "created_at", Desc: true}},
+		},
+		{
+			name:     "Field map in query order",
+			query:    "sort[name]=asc&sort[created_at]=DESC",
+			expected: []SortSpec{{Field: "name"}, {Field: "created_at", Desc: true}},
+		},
+		{
+			name:     "Missing",
+			query:    "q=1",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseOrdered(tt.query, nil)
+			assert.NoError(t, err)
+			specs, err := ParseSort(result, &SortOptions{Allowed: []string{"name", "created_at"}})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, specs)
+		})
+	}
+}
+
+func TestParseSortErrors(t *testing.T) {
+	result, _ := Parse("sort=-password", nil)
+	_, err := ParseSort(result, &SortOptions{Allowed: []string{"name"}})
+	var paramErr *ParamError
+	if assert.True(t, errors.As(err, &paramErr)) {
+		assert.Equal(t, "password", paramErr.Value)
+	}
+
+	result, _ = Parse("sort[name]=up", nil)
+	_, err = ParseSort(result, nil)
+	assert.True(t, errors.As(err, &paramErr))
+
+	// A plain map cannot tell which field came first
+	result, _ = Parse("sort[name]=asc&sort[created_at]=desc", nil)
+	_, err = ParseSort(result, nil)
+	assert.True(t, errors.As(err, &paramErr))
+}
+
+func TestParseSortSingleFieldMap(t *testing.T) {
+	result, err := Parse("sort[created_at]=desc", nil)
+	assert.NoError(t, err)
+	specs, err := ParseSort(result, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []SortSpec{{Field: "created_at", Desc: true}}, specs)
+}
+
+func TestParsePage(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected Page
+	}{
+		{
+			name:     "Defaults",
+			query:    "",
+			expected: Page{Number: 1, Size: 20},
+		},
+		{
+			name:     "Number and size",
+			query:    "page[number]=3&page[size]=10",
+			expected: Page{Number: 3, Size: 10},
+		},
+		{
+			name:     "Size clamped",
+			query:    "page[size]=500",
+			expected: Page{Number: 1, Size: 100},
+		},
+		{
+			name:     "Cursor",
+			query:    "page[after]=abc&page[size]=5",
+			expected: Page{Number: 1, Size: 5, After: "abc"},
+		},
+		{
+			name:     "Plain page number",
+			query:    "page=4",
+			expected: Page{Number: 4, Size: 20},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.query, nil)
+			assert.NoError(t, err)
+			page, err := ParsePage(result, &PageOptions{DefaultSize: 20, MaxSize: 100})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, page)
+		})
+	}
+
+	result, _ := Parse("page[number]=0", nil)
+	_, err := ParsePage(result, nil)
+	assert.Error(t, err)
+}
+
+func TestParsePageMaxSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		options  PageOptions
+		expected Page
+	}{
+		{
+			name:     "No size or default",
+			query:    "page[number]=2",
+			options:  PageOptions{MaxSize: 50},
+			expected: Page{Number: 2, Size: 50},
+		},
+		{
+			name:     "Default above max",
+			query:    "",
+			options:  PageOptions{DefaultSize: 100, MaxSize: 50},
+			expected: Page{Number: 1, Size: 50},
+		},
+		{
+			name:     "Unbounded without max",
+			query:    "",
+			options:  PageOptions{},
+			expected: Page{Number: 1, Size: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.query, nil)
+			assert.NoError(t, err)
+			page, err := ParsePage(result, &tt.options)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, page)
+		})
+	}
+}
@@ -16,6 +16,13 @@ type QueryItem struct {
 	Value string
 }
 
+// parsedValue is a single decoded parameter produced by parseValues.
+type parsedValue struct {
+	index int
+	key   string
+	value interface{}
+}
+
 // Defaults for parse options
 var defaults = ParseOptions{
 	AllowDots:                false,
@@ -41,6 +48,15 @@ var defaults = ParseOptions{
 	StrictNullHandling:       false,
 	AllowNilArrayValues:      false,
 	ThrowOnLimitExceeded:     false,
+	LiteralPlus:              false,
+	ValueDecoder:             nil,
+	Schema:                   nil,
+	AllowedKeys:              nil,
+	RejectUnknownKeys:        false,
+	PostProcess:              nil,
+	Styles:                   nil,
+	UnderscoreRootKeys:       false,
+	GroupArrayObjects:        false,
 }
 
 // ParseOptions holds options for parsing
@@ -68,6 +84,33 @@ type ParseOptions struct {
 	StrictNullHandling       bool
 	AllowNilArrayValues      bool
 	ThrowOnLimitExceeded     bool
+	LiteralPlus              bool
+	ValueDecoder             ValueDecoderFunc
+	Schema                   Schema
+	AllowedKeys              []string
+	RejectUnknownKeys        bool
+	PostProcess              *PostProcessRules
+	Styles                   map[string]ParamStyle
+	UnderscoreRootKeys       bool
+	GroupArrayObjects        bool
+}
+
+// PostProcessRules configures how PostProcessParsedObject reshapes parsed
+// results. Parse applies no rules unless ParseOptions.PostProcess is set.
+type PostProcessRules struct {
+	// ObjectKeys turns arrays holding objects with any of these keys into
+	// index-keyed objects.
+	ObjectKeys []string
+	// Operators are the keys collected, together with numeric flags, into
+	// the qs array format.
+	Operators []string
+}
+
+// QsOperatorRules holds the operator vocabulary earlier versions applied to
+// every result.
+var QsOperatorRules = PostProcessRules{
+	ObjectKeys: []string{"contains", "some", "every", "none", "length"},
+	Operators:  []string{"lt", "gt", "like", "neq", "in", "between"},
 }
 
 // DecodeFunc defines a function type for string decoding
@@ -76,6 +119,12 @@ type DecodeFunc func(string) string
 // DecoderFunc defines a function type for string decoding with context
 type DecoderFunc func(string, DecodeFunc, string, string) string
 
+// ValueDecoderFunc decodes a raw parameter value into a typed value. path is
+// the key chain the value is stored under, with brackets removed and "[]" for
+// array pushes. Keys without "=", such as a bare flag, are decoded from an
+// empty str. A returned error aborts Parse.
+type ValueDecoderFunc func(str string, decodeFunc DecodeFunc, charset string, path []string) (interface{}, error)
+
 // defaultDecoder is the default implementation for the Decoder option
 func defaultDecoder(s string, decodeFunc DecodeFunc, charset string, typ string) string {
 	return decodeFunc(s)
@@ -129,6 +178,10 @@ func parseArrayValue(val string, options ParseOptions, currentArrayLength int) i
 }
 
 func PostProcessParsedObject(obj map[string]interface{}, options *ParseOptions) map[string]interface{} {
+	if options == nil {
+		normalized := normalizeParseOptions(nil)
+		options = &normalized
+	}
 	result := processNestedStructures(obj, options)
 	if m, ok := result.(map[string]interface{}); ok {
 		return m
@@ -136,7 +189,7 @@ func PostProcessParsedObject(obj map[string]interface{}, options *ParseOptions)
 	return obj
 }
 
-func needsToBeObject(arr []interface{}) bool {
+func needsToBeObject(arr []interface{}, objectKeys []string) bool {
 	hasMapElements := false
 	hasSpecificProperties := false
 
@@ -147,7 +200,7 @@ func needsToBeObject(arr []interface{}) bool {
 			// Verifica se o mapa tem propriedades como "contains", "some", "every", etc.
 			if m, ok := v.(map[string]interface{}); ok {
 				for k := range m {
-					if k == "contains" || k == "some" || k == "every" || k == "none" || k == "length" {
+					if containsString(objectKeys, k) {
 						hasSpecificProperties = true
 						break
 					}
@@ -159,7 +212,7 @@ func needsToBeObject(arr []interface{}) bool {
 	return hasMapElements && hasSpecificProperties
 }
 
-func convertToQsArrayFormat(m map[string]interface{}) []interface{} {
+func convertToQsArrayFormat(m map[string]interface{}, operators []string) []interface{} {
 	result := []interface{}{}
 
 	for k, v := range m {
@@ -172,7 +225,7 @@ func convertToQsArrayFormat(m map[string]interface{}) []interface{} {
 
 	opMap := map[string]interface{}{}
 	for k, v := range m {
-		if k == "lt" || k == "gt" || k == "like" || k == "neq" || k == "in" || k == "between" {
+		if containsString(operators, k) {
 			opMap[k] = v
 		}
 	}
@@ -185,6 +238,17 @@ func convertToQsArrayFormat(m map[string]interface{}) []interface{} {
 }
 
 func EscapeQueryString(rawQuery string) string {
+	return escapeQueryString(rawQuery, false)
+}
+
+// escapeQueryString normalizes the encoding of every value in rawQuery. When
+// literalPlus is set, '+' is kept as a literal plus sign instead of a space.
+func escapeQueryString(rawQuery string, literalPlus bool) string {
+	unescape := url.QueryUnescape
+	if literalPlus {
+		unescape = url.PathUnescape
+	}
+
 	rawQuery = strings.ReplaceAll(rawQuery, "\t", "")
 	rawQuery = strings.ReplaceAll(rawQuery, "\n", "")
 
@@ -195,63 +259,112 @@ func EscapeQueryString(rawQuery string) string {
 			key := part[:idx]
 			value := part[idx+1:]
 
-			if unescapedValue, err := url.QueryUnescape(value); err == nil {
+			if unescapedValue, err := unescape(value); err == nil {
 				value = unescapedValue
 			}
 
 			escapedValue := url.QueryEscape(value)
-			key, _ = url.QueryUnescape(key)
+			if literalPlus {
+				escapedValue = strings.ReplaceAll(escapedValue, "+", "%20")
+			}
+			key, _ = unescape(key)
 			parts[i] = key + "=" + escapedValue
 		}
 	}
 	return strings.Join(parts, "&")
 }
 
-func Parse(str string, opts *ParseOptions) (result map[string]interface{}, err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			switch v := r.(type) {
-			case error:
-				err = v
-			case string:
-				err = errors.New(v)
-			default:
-				err = fmt.Errorf("unexpected query decoder panic: %v", r)
-			}
-			result = nil
+// recoverParseError turns a panic raised while parsing, such as an exceeded
+// limit, into the returned error.
+func recoverParseError(result *map[string]interface{}, err *error) {
+	if r := recover(); r != nil {
+		switch v := r.(type) {
+		case error:
+			*err = v
+		case string:
+			*err = errors.New(v)
+		default:
+			*err = fmt.Errorf("unexpected query decoder panic: %v", r)
 		}
-	}()
+		*result = nil
+	}
+}
+
+func Parse(str string, opts *ParseOptions) (result map[string]interface{}, err error) {
+	defer recoverParseError(&result, &err)
 
-	str = EscapeQueryString(str)
 	options := normalizeParseOptions(opts)
-	if str == "" {
-		if options.PlainObjects {
-			return map[string]interface{}{}, nil
-		}
+	urlValues := parseQuery(str, options)
+	if urlValues == nil {
 		return map[string]interface{}{}, nil
 	}
-	var urlValues [][]string
-	if s, ok := interface{}(str).(string); ok {
-		urlValues = parseValues(s, options)
-	} else {
-		return nil, errors.New("input must be a string")
+	return buildObject(urlValues, options)
+}
+
+// parseQuery decodes str into parameters in query order. It returns nil for
+// an empty query.
+func parseQuery(str string, options ParseOptions) []parsedValue {
+	str = escapeQueryString(str, options.LiteralPlus)
+	if str == "" {
+		return nil
+	}
+	return parseValues(str, options)
+}
+
+// buildObject turns decoded parameters into the nested result, applying the
+// key and value options of Parse.
+func buildObject(urlValues []parsedValue, options ParseOptions) (result map[string]interface{}, err error) {
+	defer recoverParseError(&result, &err)
+
+	urlValues = applyDuplicates(urlValues, options.Duplicates)
+	var schema []schemaEntry
+	var fieldErrors []FieldError
+	if options.Schema != nil {
+		schema = compileSchema(options.Schema)
+	}
+	var allowed [][]string
+	if options.AllowedKeys != nil {
+		allowed = compileKeyPaths(options.AllowedKeys)
+	}
+	for name, style := range options.Styles {
+		if err := style.validate(name); err != nil {
+			return nil, err
+		}
 	}
 	obj := map[string]interface{}{}
 	for _, pair := range urlValues {
-		if len(pair) != 3 {
+		value := pair.value
+		if !keyAllowed(allowed, pair.key, options) {
 			continue
 		}
-		// realIndex := pair[0]
-		key := pair[1]
-		value := pair[2]
-		newObj := parseKeys(key, value, options, true)
-		merged := Merge(obj, newObj, options)
+		if style, ok := options.Styles[pair.key]; ok {
+			value = splitStyledValue(value, style)
+		}
+		if schema != nil {
+			coerced, fieldErr := coerceValue(schema, chainPath(splitKey(pair.key, options)), value)
+			if fieldErr != nil {
+				fieldErr.Key = pair.key
+				fieldErrors = append(fieldErrors, *fieldErr)
+				continue
+			}
+			value = markDecoded(coerced)
+		}
+		var merged interface{}
+		if options.GroupArrayObjects {
+			merged = mergeGrouped(obj, splitKey(pair.key, options), value, options)
+		} else {
+			newObj := parseKeys(pair.key, value, options, true)
+			merged = Merge(obj, newObj, options)
+		}
 		if m, ok := merged.(map[string]interface{}); ok {
 			obj = m
 		} else {
 			return nil, errors.New("failed to merge objects")
 		}
 	}
+	if len(fieldErrors) > 0 {
+		return nil, &CoercionError{Fields: fieldErrors}
+	}
 	// if options.AllowSparse {
 	// 	processed := PostProcessParsedObject(obj)
 	// 	return processed, nil
@@ -261,11 +374,25 @@ func Parse(str string, opts *ParseOptions) (result map[string]interface{}, err e
 	if !ok {
 		return nil, errors.New("failed to compact object")
 	}
-	processed := PostProcessParsedObject(compacted, opts)
+	processed := PostProcessParsedObject(compacted, &options)
+	if hasTypedValues(&options) {
+		unwrapDecoded(processed)
+	}
 	return processed, nil
 }
 
 func parseKeys(givenKey string, val interface{}, options ParseOptions, valuesParsed bool) interface{} {
+	keys := splitKey(givenKey, options)
+	if keys == nil {
+		return nil
+	}
+	return parseObject(keys, val, options, valuesParsed)
+}
+
+// splitKey splits givenKey into the key chain parseObject expects, e.g.
+// "a[b][]" becomes ["a", "[b]", "[]"]. It returns nil when the key must be
+// skipped.
+func splitKey(givenKey string, options ParseOptions) []string {
 	if givenKey == "" {
 		return nil
 	}
@@ -288,8 +415,17 @@ func parseKeys(givenKey string, val interface{}, options ParseOptions, valuesPar
 		if !options.PlainObjects && parent == "__proto__" && !options.AllowPrototypes {
 			return nil
 		}
+		if options.UnderscoreRootKeys {
+			// PHP's parse_str replaces dots and spaces in the root key
+			parent = strings.NewReplacer(".", "_", " ", "_").Replace(parent)
+		}
 		keys = append(keys, parent)
 	}
+	// Depth 0 disables nesting: the whole key is a single root key, as
+	// URLSearchParams does
+	if options.Depth <= 0 {
+		return []string{key}
+	}
 	i := 0
 	seg := child.FindStringSubmatchIndex(key)
 	for options.Depth > 0 && seg != nil && len(seg) >= 2 && i < options.Depth {
@@ -308,11 +444,24 @@ func parseKeys(givenKey string, val interface{}, options ParseOptions, valuesPar
 		}
 		keys = append(keys, "["+key+"]")
 	}
-	return parseObject(keys, val, options, valuesParsed)
+	return keys
+}
+
+// chainPath strips the brackets from a key chain produced by splitKey,
+// keeping "[]" for array pushes.
+func chainPath(chain []string) []string {
+	path := make([]string, len(chain))
+	for i, segment := range chain {
+		if segment != "[]" && strings.HasPrefix(segment, "[") && strings.HasSuffix(segment, "]") {
+			segment = segment[1 : len(segment)-1]
+		}
+		path[i] = segment
+	}
+	return path
 }
 
-func parseValues(str string, options ParseOptions) [][]string {
-	result := [][]string{}
+func parseValues(str string, options ParseOptions) []parsedValue {
+	result := []parsedValue{}
 
 	cleanStr := str
 	if options.IgnoreQueryPrefix {
@@ -321,18 +470,10 @@ func parseValues(str string, options ParseOptions) [][]string {
 	cleanStr = strings.ReplaceAll(cleanStr, "%5B", "[")
 	cleanStr = strings.ReplaceAll(cleanStr, "%5D", "]")
 
-	limit := options.ParameterLimit
-	if limit == 0 {
-		limit = 1000
-	}
+	limit := parameterLimit(options)
 	parts := strings.SplitN(cleanStr, options.Delimiter, limit+1)
 	if options.ThrowOnLimitExceeded && len(parts) > limit {
-		panic(fmt.Errorf("Parameter limit exceeded. Only %d parameter%s allowed.", limit, func() string {
-			if limit == 1 {
-				return ""
-			}
-			return "s"
-		}()))
+		panic(parameterLimitError(limit))
 	}
 
 	skipIndex := -1
@@ -357,6 +498,15 @@ func parseValues(str string, options ParseOptions) [][]string {
 	if decoder == nil {
 		decoder = defaultDecoder
 	}
+	decode := Decode
+	if options.LiteralPlus {
+		decode = DecodeLiteralPlus
+	}
+
+	var allowed [][]string
+	if options.AllowedKeys != nil {
+		allowed = compileKeyPaths(options.AllowedKeys)
+	}
 
 	paramIndex := 0
 	var pos int
@@ -375,19 +525,40 @@ func parseValues(str string, options ParseOptions) [][]string {
 		var key string
 		var val interface{}
 		if pos == -1 {
-			key = decoder(part, Decode, charset, "key")
+			key = decoder(part, decode, charset, "key")
+		} else {
+			key = decoder(part[:pos], decode, charset, "key")
+		}
+		// Unknown keys are dropped before their values are decoded
+		if key != "" && !keyAllowed(allowed, key, options) {
+			continue
+		}
+		if key != "" && options.ValueDecoder != nil {
+			// Bare keys such as "debug" are decoded from an empty value
+			rawVal := ""
+			if pos != -1 {
+				rawVal = part[pos+1:]
+			}
+			typed, err := options.ValueDecoder(rawVal, decode, charset, chainPath(splitKey(key, options)))
+			if err != nil {
+				panic(fmt.Errorf("decoding value of %q: %w", key, err))
+			}
+			result = append(result, parsedValue{index: paramIndex, key: key, value: markDecoded(typed)})
+			paramIndex++
+			continue
+		}
+		if pos == -1 {
 			if options.StrictNullHandling {
 				val = nil
 			} else {
 				val = ""
 			}
 		} else {
-			key = decoder(part[:pos], Decode, charset, "key")
 			if key != "" {
 				rawVal := part[pos+1:]
 				// For simplicity, we'll convert the value directly to string
 				// since we're storing everything as strings in the result
-				val = decoder(rawVal, Decode, charset, "value") //nolint:staticcheck
+				val = decoder(rawVal, decode, charset, "value") //nolint:staticcheck
 
 				if val != nil && options.InterpretNumericEntities && charset == "iso-8859-1" { //nolint:staticcheck
 					if s, ok := val.(string); ok {
@@ -398,18 +569,17 @@ func parseValues(str string, options ParseOptions) [][]string {
 		}
 
 		if key != "" {
-			// Convert the value to string for storage in our result slice
-			valStr := ""
+			// Convert the value to string for storage in our result slice,
+			// keeping the null of a bare key under StrictNullHandling
+			var value interface{} = ""
 			if val != nil {
-				valStr = AsString(val)
+				value = AsString(val)
+			} else if options.StrictNullHandling {
+				value = nil
 			}
 
 			// Add the parameter to our result slice
-			result = append(result, []string{
-				strconv.Itoa(paramIndex),
-				key,
-				valStr,
-			})
+			result = append(result, parsedValue{index: paramIndex, key: key, value: value})
 
 			paramIndex++
 		}
@@ -418,6 +588,44 @@ func parseValues(str string, options ParseOptions) [][]string {
 	return result
 }
 
+func parameterLimit(options ParseOptions) int {
+	if options.ParameterLimit == 0 {
+		return 1000
+	}
+	return options.ParameterLimit
+}
+
+func parameterLimitError(limit int) error {
+	return fmt.Errorf("Parameter limit exceeded. Only %d parameter%s allowed.", limit, func() string {
+		if limit == 1 {
+			return ""
+		}
+		return "s"
+	}())
+}
+
+// applyDuplicates keeps only the first or last occurrence of repeated keys
+// for the "first" and "last" Duplicates modes. Keys with "[]" are array
+// pushes and are always kept.
+func applyDuplicates(values []parsedValue, duplicates string) []parsedValue {
+	if duplicates != "first" && duplicates != "last" {
+		return values
+	}
+	keep := map[string]int{}
+	for i, value := range values {
+		if _, seen := keep[value.key]; !seen || duplicates == "last" {
+			keep[value.key] = i
+		}
+	}
+	result := make([]parsedValue, 0, len(keep))
+	for i, value := range values {
+		if keep[value.key] == i || strings.Contains(value.key, "[]") {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
 func interpretNumericEntities(str string) string {
 	re := regexp.MustCompile(`&#(\d+);`)
 	return re.ReplaceAllStringFunc(str, func(s string) string {
@@ -499,10 +707,19 @@ func ArrayToObject(source []interface{}, plainObjects bool) map[int]interface{}
 	}
 	return obj
 }
+
+// Merge merges source into target in place, as Parse does for every
+// parameter. Use MergeQueries to combine results without modifying them.
 func Merge(target, source any, options ParseOptions) any {
 	if source == nil {
 		return target
 	}
+	if om, ok := target.(*OrderedMap); ok {
+		return om.merge(source, options)
+	}
+	if om, ok := source.(*OrderedMap); ok {
+		source = om.ToMap()
+	}
 
 	sourceVal := reflect.ValueOf(source)
 	if sourceVal.Kind() != reflect.Map && sourceVal.Kind() != reflect.Slice && sourceVal.Kind() != reflect.Array {
@@ -559,6 +776,11 @@ func Merge(target, source any, options ParseOptions) any {
 		if s, ok := source.(map[string]any); ok {
 			for key, value := range s {
 				if value == nil {
+					// Keep nulls returned by a ValueDecoder or kept by
+					// StrictNullHandling without overwriting existing values.
+					if _, exists := mt[key]; !exists && keepsNulls(options) {
+						mt[key] = nil
+					}
 					continue
 				}
 
@@ -640,6 +862,16 @@ func Decode(str string) string {
 	return decoded
 }
 
+// DecodeLiteralPlus decodes percent-encoded sequences like Decode, but keeps
+// '+' as a literal plus sign, as RFC3986 does.
+func DecodeLiteralPlus(str string) string {
+	decoded, err := url.PathUnescape(str)
+	if err != nil {
+		return str
+	}
+	return decoded
+}
+
 const limit = 1024
 
 // encode percent-encodes a string according to RFC3986 or RFC1738
@@ -792,8 +1024,8 @@ func processNestedStructures(obj interface{}, options *ParseOptions) interface{}
 		for k, v := range m {
 			m[k] = processNestedStructures(v, options)
 		}
-		if shouldConvertToArray(m) {
-			return convertToQsArrayFormat(m)
+		if rules := options.PostProcess; rules != nil && shouldConvertToArray(m) {
+			return convertToQsArrayFormat(m, rules.Operators)
 		}
 		return m
 	}
@@ -806,7 +1038,7 @@ func processNestedStructures(obj interface{}, options *ParseOptions) interface{}
 			newArr = append(newArr, processNestedStructures(v, options))
 		}
 
-		if needsToBeObject(newArr) {
+		if rules := options.PostProcess; rules != nil && needsToBeObject(newArr, rules.ObjectKeys) {
 			return ArrayToObject(newArr, true)
 		}
 		return newArr
@@ -821,6 +1053,64 @@ func processNestedStructures(obj interface{}, options *ParseOptions) interface{}
 	return obj
 }
 
+// hasTypedValues reports whether parsed leaves may hold non-string values.
+func hasTypedValues(options *ParseOptions) bool {
+	return options.ValueDecoder != nil || options.Schema != nil
+}
+
+// keepsNulls reports whether Merge keeps explicit null values.
+func keepsNulls(options ParseOptions) bool {
+	return options.ValueDecoder != nil || options.StrictNullHandling
+}
+
+// decodedBool is a bool returned by a ValueDecoder or Schema. It keeps typed
+// values apart from the true markers Merge adds, which post-processing turns
+// into strings, until unwrapDecoded restores it.
+type decodedBool bool
+
+// markDecoded returns value with its bools wrapped in decodedBool, copying
+// arrays and objects rather than modifying the decoder's values.
+func markDecoded(value interface{}) interface{} {
+	switch v := value.(type) {
+	case bool:
+		return decodedBool(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = markDecoded(item)
+		}
+		return items
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			obj[k] = markDecoded(item)
+		}
+		return obj
+	}
+	return value
+}
+
+// unwrapDecoded turns the decodedBool values of a result back into bools.
+func unwrapDecoded(value interface{}) interface{} {
+	switch v := value.(type) {
+	case decodedBool:
+		return bool(v)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = unwrapDecoded(item)
+		}
+	case map[string]interface{}:
+		for k, item := range v {
+			v[k] = unwrapDecoded(item)
+		}
+	case map[int]interface{}:
+		for k, item := range v {
+			v[k] = unwrapDecoded(item)
+		}
+	}
+	return value
+}
+
 func shouldConvertToArray(m map[string]interface{}) bool {
 	hasNum := false
 	hasOps := false
@@ -840,6 +1130,15 @@ func shouldConvertToArray(m map[string]interface{}) bool {
 	return (hasNum && hasOps) || (hasBool && hasObj)
 }
 
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
 func Compact(value interface{}) interface{} {
 	if arr, ok := value.([]interface{}); ok {
 		for len(arr) > 0 && arr[len(arr)-1] == nil {
@@ -853,5 +1152,11 @@ func Compact(value interface{}) interface{} {
 		}
 		return m
 	}
+	if m, ok := value.(*OrderedMap); ok {
+		for _, k := range m.keys {
+			m.values[k] = Compact(m.values[k])
+		}
+		return m
+	}
 	return value
 }
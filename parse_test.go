@@ -1,6 +1,7 @@
 package goqs
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -91,3 +92,227 @@ func TestDecodeQueryFormats(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeLiteralPlus(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		options  *ParseOptions
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Plus decodes to space by default",
+			query:    "a=1+2&b=c%2Bd",
+			options:  nil,
+			expected: map[string]interface{}{"a": "1 2", "b": "c+d"},
+		},
+		{
+			name:     "Plus kept literal",
+			query:    "token=ab+cd%2Bef==&q=x%20y",
+			options:  &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, LiteralPlus: true},
+			expected: map[string]interface{}{"token": "ab+cd+ef==", "q": "x y"},
+		},
+		{
+			name:     "Plus kept literal in keys",
+			query:    "a+b[c+d]=1",
+			options:  &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, LiteralPlus: true},
+			expected: map[string]interface{}{"a+b": map[string]interface{}{"c+d": "1"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, tt.options)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestValueDecoder(t *testing.T) {
+	decoder := func(str string, decode DecodeFunc, charset string, path []string) (interface{}, error) {
+		s := decode(str)
+		switch {
+		case s == "true" || s == "false":
+			return s == "true", nil
+		case s == "null":
+			return nil, nil
+		case path[len(path)-1] == "age":
+			return strconv.Atoi(s)
+		}
+		return s, nil
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected map[string]interface{}
+		err      bool
+	}{
+		{
+			name:     "Typed leaves",
+			query:    "user[age]=30&user[admin]=true&user[name]=Alice",
+			expected: map[string]interface{}{"user": map[string]interface{}{"age": 30, "admin": true, "name": "Alice"}},
+		},
+		{
+			name:     "Typed array values",
+			query:    "flags[]=true&flags[]=false",
+			expected: map[string]interface{}{"flags": []interface{}{true, false}},
+		},
+		{
+			name:     "Null values",
+			query:    "a=null&b=1",
+			expected: map[string]interface{}{"a": nil, "b": "1"},
+		},
+		{
+			name:  "Decoder error",
+			query: "user[age]=old",
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, ValueDecoder: decoder})
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestValueDecoderKeepsMarkers(t *testing.T) {
+	decoder := func(str string, decode DecodeFunc, charset string, path []string) (interface{}, error) {
+		s := decode(str)
+		if path[len(path)-1] == "debug" {
+			return s != "false", nil
+		}
+		if s == "yes" {
+			return true, nil
+		}
+		return s, nil
+	}
+	options := &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, PlainObjects: true, ValueDecoder: decoder}
+
+	res, err := Parse("a[b]=1&a=c", options)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": "1", "c": "true"}}, res)
+
+	res, err = Parse("debug&x[]=yes&x[k]=no", options)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"debug": true, "x": map[string]interface{}{"0": true, "k": "no"}}, res)
+}
+
+func TestMergeDropsNullsWithoutValueDecoder(t *testing.T) {
+	merged := Merge(map[string]interface{}{"a": "1"}, map[string]interface{}{"b": nil}, defaults)
+	assert.Equal(t, map[string]interface{}{"a": "1"}, merged)
+
+	options := defaults
+	options.PlainObjects = true
+	res, err := Parse("a[__proto__]=1&b=2", &options)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"b": "2"}, res)
+
+	ordered, err := ParseOrdered("a[__proto__]=1&b=2", &options)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b"}, ordered.Keys())
+}
+
+func TestPostProcessRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		rules    *PostProcessRules
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Operator words are plain keys by default",
+			query:    "a[0][contains]=x",
+			rules:    nil,
+			expected: map[string]interface{}{"a": []interface{}{map[string]interface{}{"contains": "x"}}},
+		},
+		{
+			name:     "Object keys from qs rules",
+			query:    "a[0][contains]=x",
+			rules:    &QsOperatorRules,
+			expected: map[string]interface{}{"a": map[int]interface{}{0: map[string]interface{}{"contains": "x"}}},
+		},
+		{
+			name:     "Custom object keys",
+			query:    "a[0][has]=x&b[0][contains]=y",
+			rules:    &PostProcessRules{ObjectKeys: []string{"has"}},
+			expected: map[string]interface{}{"a": map[int]interface{}{0: map[string]interface{}{"has": "x"}}, "b": []interface{}{map[string]interface{}{"contains": "y"}}},
+		},
+		{
+			name:     "qs rules keep indices next to operators",
+			query:    "a[0]=x&a[in]=y",
+			rules:    &QsOperatorRules,
+			expected: map[string]interface{}{"a": map[string]interface{}{"0": "x", "in": "y"}},
+		},
+		{
+			name:     "qs rules keep numeric keys next to comparisons",
+			query:    "price[0]=5&price[lt]=10",
+			rules:    &QsOperatorRules,
+			expected: map[string]interface{}{"price": map[string]interface{}{"0": "5", "lt": "10"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, PostProcess: tt.rules})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestDuplicates(t *testing.T) {
+	tests := []struct {
+		duplicates string
+		expected   map[string]interface{}
+	}{
+		{duplicates: "combine", expected: map[string]interface{}{"a": []interface{}{"1", "2"}, "b": []interface{}{"x", "y"}}},
+		{duplicates: "first", expected: map[string]interface{}{"a": "1", "b": []interface{}{"x", "y"}}},
+		{duplicates: "last", expected: map[string]interface{}{"a": "2", "b": []interface{}{"x", "y"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.duplicates, func(t *testing.T) {
+			res, err := Parse("a=1&b[]=x&a=2&b[]=y", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, Duplicates: tt.duplicates})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestDepthZero(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected map[string]interface{}
+	}{
+		{query: "a[b]=1&c=2", expected: map[string]interface{}{"a[b]": "1", "c": "2"}},
+		{query: "a[b][c]=1", expected: map[string]interface{}{"a[b][c]": "1"}},
+		{query: "a[]=1&a[]=2", expected: map[string]interface{}{"a[]": []interface{}{"1", "2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestStrictNullHandling(t *testing.T) {
+	res, err := Parse("a&b=&c[d]", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, StrictNullHandling: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": nil, "b": "", "c": map[string]interface{}{"d": nil}}, res)
+
+	res, err = Parse("a&b=", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": "", "b": ""}, res)
+}
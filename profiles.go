@@ -0,0 +1,61 @@
+package goqs
+
+// Profile bundles the parse and stringify options matching another query
+// string implementation.
+type Profile struct {
+	Parse     ParseOptions
+	Stringify StringifyOptions
+}
+
+// QsProfile matches the javascript qs library defaults.
+func QsProfile() Profile {
+	return Profile{
+		Parse:     normalizeParseOptions(nil),
+		Stringify: normalizeStringifyOptions(nil),
+	}
+}
+
+// PHPProfile matches PHP's parse_str and http_build_query: dots and spaces
+// in root keys become underscores, the last of repeated keys wins and spaces
+// are written as '+'.
+func PHPProfile() Profile {
+	parse := normalizeParseOptions(nil)
+	parse.Depth = 64
+	parse.Duplicates = "last"
+	parse.UnderscoreRootKeys = true
+
+	stringify := normalizeStringifyOptions(nil)
+	stringify.Format = RFC1738
+	return Profile{Parse: parse, Stringify: stringify}
+}
+
+// RackProfile matches Rack and Rails: numeric keys are object keys rather
+// than array indices, only "[]" builds arrays, items[][name] parameters are
+// grouped into objects, the last of repeated keys wins and arrays are written
+// with empty brackets.
+func RackProfile() Profile {
+	parse := normalizeParseOptions(nil)
+	parse.ArrayLimit = -1
+	parse.Depth = 32
+	parse.Duplicates = "last"
+	parse.GroupArrayObjects = true
+
+	stringify := normalizeStringifyOptions(nil)
+	stringify.ArrayFormat = ArrayFormatBrackets
+	stringify.Format = RFC1738
+	return Profile{Parse: parse, Stringify: stringify}
+}
+
+// URLSearchParamsProfile matches the browser URLSearchParams API, which has
+// no nesting: keys are kept verbatim and repeated keys are collected into
+// arrays, written back as repeated parameters.
+func URLSearchParamsProfile() Profile {
+	parse := normalizeParseOptions(nil)
+	parse.Depth = 0
+	parse.ParseArrays = false
+
+	stringify := normalizeStringifyOptions(nil)
+	stringify.ArrayFormat = ArrayFormatRepeat
+	stringify.Format = RFC1738
+	return Profile{Parse: parse, Stringify: stringify}
+}
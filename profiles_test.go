@@ -0,0 +1,72 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProfiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  Profile
+		query    string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "qs",
+			profile:  QsProfile(),
+			query:    "a.b=1&a[c][0]=2&d=1&d=2",
+			expected: map[string]interface{}{"a.b": "1", "a": map[string]interface{}{"c": []interface{}{"2"}}, "d": []interface{}{"1", "2"}},
+		},
+		{
+			name:     "PHP",
+			profile:  PHPProfile(),
+			query:    "a.b=1&first name=x&c[d.e]=2&f=1&f=2&g[]=1&g[]=2",
+			expected: map[string]interface{}{"a_b": "1", "first_name": "x", "c": map[string]interface{}{"d.e": "2"}, "f": "2", "g": []interface{}{"1", "2"}},
+		},
+		{
+			name:     "Rack",
+			profile:  RackProfile(),
+			query:    "a[0]=x&b[]=1&b[]=2&c=1&c=2",
+			expected: map[string]interface{}{"a": map[string]interface{}{"0": "x"}, "b": []interface{}{"1", "2"}, "c": "2"},
+		},
+		{
+			name:     "URLSearchParams",
+			profile:  URLSearchParamsProfile(),
+			query:    "a[b]=1&c=1&c=2",
+			expected: map[string]interface{}{"a[b]": "1", "c": []interface{}{"1", "2"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &tt.profile.Parse)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestProfilesStringify(t *testing.T) {
+	obj := map[string]interface{}{"a": []interface{}{"x y", "z"}}
+
+	tests := []struct {
+		name     string
+		profile  Profile
+		expected string
+	}{
+		{name: "qs", profile: QsProfile(), expected: "a%5B0%5D=x%20y&a%5B1%5D=z"},
+		{name: "PHP", profile: PHPProfile(), expected: "a%5B0%5D=x+y&a%5B1%5D=z"},
+		{name: "Rack", profile: RackProfile(), expected: "a%5B%5D=x+y&a%5B%5D=z"},
+		{name: "URLSearchParams", profile: URLSearchParamsProfile(), expected: "a=x+y&a=z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Stringify(obj, &tt.profile.Stringify)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package goqs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind identifies the type a parameter value is coerced to.
+type Kind int
+
+const (
+	KindString Kind = iota
+	KindInt
+	KindFloat
+	KindBool
+	KindTime
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindInt:
+		return "int"
+	case KindFloat:
+		return "float"
+	case KindBool:
+		return "bool"
+	case KindTime:
+		return "time"
+	}
+	return "unknown"
+}
+
+// Coercion describes how a parameter value is converted. Layout is used by
+// KindTime and defaults to time.RFC3339.
+type Coercion struct {
+	Kind   Kind
+	Layout string
+}
+
+// Schema maps key paths such as "filter.price.gte", "page" or "tags[]" to the
+// type their values are coerced to. "[]" matches any array index and "*" any
+// single key. When several paths match, the one with fewer wildcards wins.
+type Schema map[string]Coercion
+
+// FieldError describes a parameter that failed schema coercion.
+type FieldError struct {
+	Key   string
+	Value string
+	Kind  Kind
+	Err   error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: cannot convert %q to %s: %v", e.Key, e.Value, e.Kind, e.Err)
+}
+
+// CoercionError lists every parameter that failed schema coercion.
+type CoercionError struct {
+	Fields []FieldError
+}
+
+func (e *CoercionError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Error()
+	}
+	return "invalid parameters: " + strings.Join(messages, "; ")
+}
+
+type schemaEntry struct {
+	pattern  string
+	segments []string
+	coercion Coercion
+}
+
+// compileSchema orders the schema paths from the most to the least specific.
+func compileSchema(schema Schema) []schemaEntry {
+	entries := make([]schemaEntry, 0, len(schema))
+	for pattern, coercion := range schema {
+		entries = append(entries, schemaEntry{pattern: pattern, segments: splitPath(pattern), coercion: coercion})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		wi, wj := wildcards(entries[i].segments), wildcards(entries[j].segments)
+		if wi != wj {
+			return wi < wj
+		}
+		return entries[i].pattern < entries[j].pattern
+	})
+	return entries
+}
+
+// coerceValue converts val according to the first entry matching path.
+// Array items, such as split styled values, are matched as path[]. Values
+// that are not strings or match no entry are returned unchanged.
+func coerceValue(entries []schemaEntry, path []string, val interface{}) (interface{}, *FieldError) {
+	if items, ok := val.([]interface{}); ok {
+		itemPath := append(append([]string{}, path...), "[]")
+		coerced := make([]interface{}, len(items))
+		for i, item := range items {
+			c, err := coerceValue(entries, itemPath, item)
+			if err != nil {
+				return nil, err
+			}
+			coerced[i] = c
+		}
+		return coerced, nil
+	}
+	s, ok := val.(string)
+	if !ok {
+		return val, nil
+	}
+	for _, entry := range entries {
+		if !matchPath(entry.segments, path) {
+			continue
+		}
+		coerced, err := coerce(s, entry.coercion)
+		if err != nil {
+			return nil, &FieldError{Value: s, Kind: entry.coercion.Kind, Err: err}
+		}
+		return coerced, nil
+	}
+	return val, nil
+}
+
+func coerce(s string, c Coercion) (interface{}, error) {
+	switch c.Kind {
+	case KindInt:
+		return strconv.Atoi(s)
+	case KindFloat:
+		return strconv.ParseFloat(s, 64)
+	case KindBool:
+		return strconv.ParseBool(s)
+	case KindTime:
+		layout := c.Layout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return time.Parse(layout, s)
+	}
+	return s, nil
+}
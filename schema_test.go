@@ -0,0 +1,72 @@
+package goqs
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWithSchema(t *testing.T) {
+	schema := Schema{
+		"page":             {Kind: KindInt},
+		"filter.price.gte": {Kind: KindFloat},
+		"filter.*.active":  {Kind: KindBool},
+		"tags[]":           {Kind: KindInt},
+		"since":            {Kind: KindTime, Layout: "2006-01-02"},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Top level values",
+			query:    "page=2&q=shoes",
+			expected: map[string]interface{}{"page": 2, "q": "shoes"},
+		},
+		{
+			name:  "Nested and wildcard paths",
+			query: "filter[price][gte]=9.5&filter[user][active]=true",
+			expected: map[string]interface{}{"filter": map[string]interface{}{
+				"price": map[string]interface{}{"gte": 9.5},
+				"user":  map[string]interface{}{"active": true},
+			}},
+		},
+		{
+			name:     "Array values",
+			query:    "tags[]=1&tags[1]=2",
+			expected: map[string]interface{}{"tags": []interface{}{1, 2}},
+		},
+		{
+			name:     "Time values",
+			query:    "since=2024-05-01",
+			expected: map[string]interface{}{"since": time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, Schema: schema})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestParseWithSchemaErrors(t *testing.T) {
+	schema := Schema{"page": {Kind: KindInt}, "tags[]": {Kind: KindInt}}
+
+	res, err := Parse("page=two&tags[]=1&tags[]=x", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, Schema: schema})
+	assert.Nil(t, res)
+
+	var coercionErr *CoercionError
+	if assert.True(t, errors.As(err, &coercionErr)) {
+		assert.Len(t, coercionErr.Fields, 2)
+		assert.Equal(t, "page", coercionErr.Fields[0].Key)
+		assert.Equal(t, "tags[]", coercionErr.Fields[1].Key)
+		assert.Equal(t, "x", coercionErr.Fields[1].Value)
+	}
+}
@@ -0,0 +1,264 @@
+package goqs
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ArrayFormat selects how Stringify serializes arrays.
+type ArrayFormat string
+
+const (
+	// ArrayFormatIndices serializes arrays as a[0]=b&a[1]=c.
+	ArrayFormatIndices ArrayFormat = "indices"
+	// ArrayFormatBrackets serializes arrays as a[]=b&a[]=c.
+	ArrayFormatBrackets ArrayFormat = "brackets"
+	// ArrayFormatRepeat serializes arrays as a=b&a=c.
+	ArrayFormatRepeat ArrayFormat = "repeat"
+	// ArrayFormatComma serializes arrays of scalars as a=b,c.
+	ArrayFormatComma ArrayFormat = "comma"
+)
+
+// Defaults for stringify options
+var stringifyDefaults = StringifyOptions{
+	AddQueryPrefix:     false,
+	AllowDots:          false,
+	ArrayFormat:        ArrayFormatIndices,
+	Charset:            "utf-8",
+	Delimiter:          "&",
+	EncodeValuesOnly:   false,
+	Format:             DefaultRFCFormat,
+	SkipEncode:         false,
+	SkipNulls:          false,
+	Sort:               nil,
+	StrictNullHandling: false,
+	Styles:             nil,
+}
+
+// StringifyOptions holds options for stringifying. Map keys are written in
+// lexical order, and OrderedMap keys in their own order, unless Sort is set.
+// Styles sets the OpenAPI serialization style of top-level parameters. Keys
+// and values are percent-encoded unless SkipEncode is set; EncodeValuesOnly
+// leaves keys raw.
+type StringifyOptions struct {
+	AddQueryPrefix     bool
+	AllowDots          bool
+	ArrayFormat        ArrayFormat
+	Charset            string
+	Delimiter          string
+	EncodeValuesOnly   bool
+	Format             RFCFormat
+	SkipEncode         bool
+	SkipNulls          bool
+	Sort               func(a, b string) bool
+	StrictNullHandling bool
+	Styles             map[string]ParamStyle
+}
+
+func normalizeStringifyOptions(opts *StringifyOptions) StringifyOptions {
+	if opts == nil {
+		return stringifyDefaults
+	}
+
+	o := *opts
+	if o.ArrayFormat == "" {
+		o.ArrayFormat = stringifyDefaults.ArrayFormat
+	}
+	if o.Charset == "" {
+		o.Charset = stringifyDefaults.Charset
+	}
+	if o.Delimiter == "" {
+		o.Delimiter = stringifyDefaults.Delimiter
+	}
+	if o.Format == "" {
+		o.Format = stringifyDefaults.Format
+	}
+	return o
+}
+
+// queryPair is a single key with its unencoded values. Values holding more
+// than one entry are joined with sep, a comma by default.
+type queryPair struct {
+	key    string
+	values []string
+	sep    string
+	null   bool
+}
+
+// Stringify serializes a nested object, such as a Parse result, into a query
+// string using qs bracket notation.
+func Stringify(obj interface{}, opts *StringifyOptions) (string, error) {
+	options := normalizeStringifyOptions(opts)
+	if !isObject(obj) {
+		return "", errors.New("stringify: value must be an object")
+	}
+	for name, style := range options.Styles {
+		if err := style.validate(name); err != nil {
+			return "", err
+		}
+	}
+
+	pairs := appendPairs(nil, "", obj, options)
+	parts := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		key := pair.key
+		if !options.SkipEncode && !options.EncodeValuesOnly {
+			key = encodeComponent(key, options)
+		}
+		if pair.null {
+			if options.StrictNullHandling {
+				parts = append(parts, key)
+			} else {
+				parts = append(parts, key+"=")
+			}
+			continue
+		}
+		values := pair.values
+		sep := pair.sep
+		if sep == "" {
+			sep = ","
+		}
+		if !options.SkipEncode {
+			values = make([]string, len(pair.values))
+			for i, value := range pair.values {
+				values[i] = encodeComponent(value, options)
+			}
+			// spaceDelimited separators must be encoded
+			if sep == " " {
+				sep = encodeComponent(sep, options)
+			}
+		}
+		parts = append(parts, key+"="+strings.Join(values, sep))
+	}
+
+	query := strings.Join(parts, options.Delimiter)
+	if options.AddQueryPrefix && query != "" {
+		query = "?" + query
+	}
+	return query, nil
+}
+
+func encodeComponent(str string, options StringifyOptions) string {
+	return Formatters[options.Format](Encode(str, options.Charset, "", string(options.Format)))
+}
+
+func isObject(value interface{}) bool {
+	switch value.(type) {
+	case map[string]interface{}, map[int]interface{}, *OrderedMap:
+		return true
+	}
+	return false
+}
+
+// appendPairs flattens value under prefix into pairs, in stringify order.
+func appendPairs(pairs []queryPair, prefix string, value interface{}, options StringifyOptions) []queryPair {
+	switch v := value.(type) {
+	case nil:
+		if options.SkipNulls || prefix == "" {
+			return pairs
+		}
+		return append(pairs, queryPair{key: prefix, null: true})
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sortKeys(keys, options)
+		return appendObjectPairs(pairs, prefix, keys, v, options)
+	case *OrderedMap:
+		keys := v.Keys()
+		if options.Sort != nil {
+			sortKeys(keys, options)
+		}
+		return appendObjectPairs(pairs, prefix, keys, v.values, options)
+	case map[int]interface{}:
+		indices := make([]int, 0, len(v))
+		for i := range v {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+		for _, i := range indices {
+			pairs = appendPairs(pairs, childKey(prefix, strconv.Itoa(i), options), v[i], options)
+		}
+		return pairs
+	case []string:
+		items := make([]interface{}, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return appendArrayPairs(pairs, prefix, items, options)
+	case []interface{}:
+		return appendArrayPairs(pairs, prefix, v, options)
+	}
+	if prefix == "" {
+		return pairs
+	}
+	return append(pairs, queryPair{key: prefix, values: []string{AsString(value)}})
+}
+
+func appendObjectPairs(pairs []queryPair, prefix string, keys []string, values map[string]interface{}, options StringifyOptions) []queryPair {
+	for _, k := range keys {
+		if style, ok := options.Styles[k]; ok && prefix == "" {
+			pairs = appendStyledPairs(pairs, k, values[k], style, options)
+			continue
+		}
+		pairs = appendPairs(pairs, childKey(prefix, k, options), values[k], options)
+	}
+	return pairs
+}
+
+func appendArrayPairs(pairs []queryPair, prefix string, items []interface{}, options StringifyOptions) []queryPair {
+	if len(items) == 0 {
+		return pairs
+	}
+	if options.ArrayFormat == ArrayFormatComma && allScalars(items) {
+		values := make([]string, len(items))
+		for i, item := range items {
+			if item != nil {
+				values[i] = AsString(item)
+			}
+		}
+		return append(pairs, queryPair{key: prefix, values: values})
+	}
+	for i, item := range items {
+		key := prefix
+		switch options.ArrayFormat {
+		case ArrayFormatIndices, ArrayFormatComma:
+			key = childKey(prefix, strconv.Itoa(i), options)
+		case ArrayFormatBrackets:
+			key = prefix + "[]"
+		}
+		pairs = appendPairs(pairs, key, item, options)
+	}
+	return pairs
+}
+
+func childKey(prefix, key string, options StringifyOptions) string {
+	if prefix == "" {
+		return key
+	}
+	if options.AllowDots {
+		return prefix + "." + key
+	}
+	return prefix + "[" + key + "]"
+}
+
+func sortKeys(keys []string, options StringifyOptions) {
+	if options.Sort != nil {
+		sort.Slice(keys, func(i, j int) bool { return options.Sort(keys[i], keys[j]) })
+		return
+	}
+	sort.Strings(keys)
+}
+
+func allScalars(items []interface{}) bool {
+	for _, item := range items {
+		switch item.(type) {
+		case map[string]interface{}, map[int]interface{}, *OrderedMap, []interface{}, []string:
+			return false
+		}
+	}
+	return true
+}
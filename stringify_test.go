@@ -0,0 +1,110 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStringify(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      map[string]interface{}
+		options  *StringifyOptions
+		expected string
+	}{
+		{
+			name:     "Empty object",
+			obj:      map[string]interface{}{},
+			expected: "",
+		},
+		{
+			name:     "Simple parameters",
+			obj:      map[string]interface{}{"b": "hello world", "a": "1"},
+			expected: "a=1&b=hello%20world",
+		},
+		{
+			name:     "Nested objects",
+			obj:      map[string]interface{}{"user": map[string]interface{}{"name": "Alice", "age": "30"}},
+			expected: "user%5Bage%5D=30&user%5Bname%5D=Alice",
+		},
+		{
+			name:     "Encode values only",
+			obj:      map[string]interface{}{"user": map[string]interface{}{"name": "A&B"}},
+			options:  &StringifyOptions{EncodeValuesOnly: true},
+			expected: "user[name]=A%26B",
+		},
+		{
+			name:     "Array indices",
+			obj:      map[string]interface{}{"a": []interface{}{"x", "y"}},
+			options:  &StringifyOptions{SkipEncode: true},
+			expected: "a[0]=x&a[1]=y",
+		},
+		{
+			name:     "Array brackets",
+			obj:      map[string]interface{}{"a": []interface{}{"x", map[string]interface{}{"b": "y"}}},
+			options:  &StringifyOptions{ArrayFormat: ArrayFormatBrackets, SkipEncode: true},
+			expected: "a[]=x&a[][b]=y",
+		},
+		{
+			name:     "Array repeat",
+			obj:      map[string]interface{}{"a": []string{"x", "y"}},
+			options:  &StringifyOptions{ArrayFormat: ArrayFormatRepeat},
+			expected: "a=x&a=y",
+		},
+		{
+			name:     "Options without SkipEncode encode",
+			obj:      map[string]interface{}{"q": "a&admin=1", "tags": []interface{}{"x y"}},
+			options:  &StringifyOptions{ArrayFormat: ArrayFormatBrackets},
+			expected: "q=a%26admin%3D1&tags%5B%5D=x%20y",
+		},
+		{
+			name:     "Array comma",
+			obj:      map[string]interface{}{"a": []interface{}{"x y", "z"}},
+			options:  &StringifyOptions{ArrayFormat: ArrayFormatComma},
+			expected: "a=x%20y,z",
+		},
+		{
+			name:     "Dots and RFC1738",
+			obj:      map[string]interface{}{"a": map[string]interface{}{"b": "c d"}},
+			options:  &StringifyOptions{AllowDots: true, Format: RFC1738},
+			expected: "a.b=c+d",
+		},
+		{
+			name:     "Nulls",
+			obj:      map[string]interface{}{"a": nil, "b": ""},
+			options:  &StringifyOptions{StrictNullHandling: true},
+			expected: "a&b=",
+		},
+		{
+			name:     "Nulls encoded",
+			obj:      map[string]interface{}{"a[b]": nil},
+			options:  &StringifyOptions{StrictNullHandling: true},
+			expected: "a%5Bb%5D",
+		},
+		{
+			name:     "Query prefix",
+			obj:      map[string]interface{}{"a": "1"},
+			options:  &StringifyOptions{AddQueryPrefix: true},
+			expected: "?a=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Stringify(tt.obj, tt.options)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
+
+func TestStringifyRoundTrip(t *testing.T) {
+	query := "a[0]=1&a[1]=2&user[name]=Alice"
+	parsed, err := Parse(query, nil)
+	assert.NoError(t, err)
+
+	res, err := Stringify(parsed, &StringifyOptions{SkipEncode: true})
+	assert.NoError(t, err)
+	assert.Equal(t, query, res)
+}
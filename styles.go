@@ -0,0 +1,135 @@
+package goqs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style is an OpenAPI 3 query parameter serialization style.
+type Style string
+
+const (
+	// StyleForm serializes arrays as a=x&a=y, or a=x,y without explode.
+	StyleForm Style = "form"
+	// StyleSpaceDelimited serializes arrays as a=x%20y.
+	StyleSpaceDelimited Style = "spaceDelimited"
+	// StylePipeDelimited serializes arrays as a=x|y.
+	StylePipeDelimited Style = "pipeDelimited"
+	// StyleDeepObject serializes objects with bracket nesting, a[b]=c.
+	StyleDeepObject Style = "deepObject"
+)
+
+// ParamStyle describes how a top-level parameter is serialized, following
+// the OpenAPI style and explode fields.
+type ParamStyle struct {
+	Style   Style
+	Explode bool
+}
+
+func (p ParamStyle) validate(name string) error {
+	switch p.Style {
+	case StyleForm, StyleSpaceDelimited, StylePipeDelimited, StyleDeepObject:
+		return nil
+	}
+	return fmt.Errorf("unsupported style %q for parameter %q", p.Style, name)
+}
+
+// delimiter returns the separator of array values, or "" when values are
+// exploded into repeated parameters.
+func (p ParamStyle) delimiter() string {
+	if p.Explode {
+		return ""
+	}
+	switch p.Style {
+	case StyleForm:
+		return ","
+	case StyleSpaceDelimited:
+		return " "
+	case StylePipeDelimited:
+		return "|"
+	}
+	return ""
+}
+
+// splitStyledValue splits a delimited value of a styled parameter into an
+// array. Other values are returned unchanged.
+func splitStyledValue(value interface{}, style ParamStyle) interface{} {
+	s, ok := value.(string)
+	delimiter := style.delimiter()
+	if !ok || s == "" || delimiter == "" {
+		return value
+	}
+	parts := strings.Split(s, delimiter)
+	items := make([]interface{}, len(parts))
+	for i, part := range parts {
+		items[i] = part
+	}
+	return items
+}
+
+// appendStyledPairs serializes the top-level parameter key using style.
+func appendStyledPairs(pairs []queryPair, key string, value interface{}, style ParamStyle, options StringifyOptions) []queryPair {
+	if style.Style == StyleDeepObject {
+		options.AllowDots = false
+		return appendPairs(pairs, key, value, options)
+	}
+
+	var keys []string
+	var m map[string]interface{}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sortKeys(keys, options)
+		m = v
+	case *OrderedMap:
+		keys, m = v.Keys(), v.values
+	}
+	if m != nil {
+		if style.Explode {
+			for _, k := range keys {
+				pairs = append(pairs, queryPair{key: k, values: []string{styledString(m[k])}})
+			}
+			return pairs
+		}
+		values := make([]string, 0, len(keys)*2)
+		for _, k := range keys {
+			values = append(values, k, styledString(m[k]))
+		}
+		return append(pairs, queryPair{key: key, values: values, sep: ","})
+	}
+
+	items, ok := value.([]interface{})
+	if !ok {
+		if list, isList := value.([]string); isList {
+			items = make([]interface{}, len(list))
+			for i, s := range list {
+				items[i] = s
+			}
+			ok = true
+		}
+	}
+	if !ok {
+		return appendPairs(pairs, key, value, options)
+	}
+
+	if delimiter := style.delimiter(); delimiter != "" {
+		values := make([]string, len(items))
+		for i, item := range items {
+			values[i] = styledString(item)
+		}
+		return append(pairs, queryPair{key: key, values: values, sep: delimiter})
+	}
+	for _, item := range items {
+		pairs = append(pairs, queryPair{key: key, values: []string{styledString(item)}})
+	}
+	return pairs
+}
+
+func styledString(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+	return AsString(value)
+}
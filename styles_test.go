@@ -0,0 +1,125 @@
+package goqs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStyles(t *testing.T) {
+	styles := map[string]ParamStyle{
+		"ids":   {Style: StyleForm},
+		"tags":  {Style: StyleSpaceDelimited},
+		"color": {Style: StylePipeDelimited},
+		"names": {Style: StyleForm, Explode: true},
+		"user":  {Style: StyleDeepObject, Explode: true},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "Form without explode",
+			query:    "ids=1,2,3",
+			expected: map[string]interface{}{"ids": []interface{}{"1", "2", "3"}},
+		},
+		{
+			name:     "Space delimited",
+			query:    "tags=a%20b",
+			expected: map[string]interface{}{"tags": []interface{}{"a", "b"}},
+		},
+		{
+			name:     "Pipe delimited",
+			query:    "color=blue|black",
+			expected: map[string]interface{}{"color": []interface{}{"blue", "black"}},
+		},
+		{
+			name:     "Form with explode",
+			query:    "names=a,b&names=c",
+			expected: map[string]interface{}{"names": []interface{}{"a,b", "c"}},
+		},
+		{
+			name:     "Deep object",
+			query:    "user[name]=Alice&user[role]=admin",
+			expected: map[string]interface{}{"user": map[string]interface{}{"name": "Alice", "role": "admin"}},
+		},
+		{
+			name:     "Unstyled parameters keep commas",
+			query:    "q=a,b",
+			expected: map[string]interface{}{"q": "a,b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Parse(tt.query, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, Styles: styles})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+
+	_, err := Parse("a=1", &ParseOptions{Styles: map[string]ParamStyle{"a": {Style: "matrix"}}})
+	assert.Error(t, err)
+}
+
+func TestStringifyStyles(t *testing.T) {
+	tests := []struct {
+		name     string
+		obj      map[string]interface{}
+		style    ParamStyle
+		expected string
+	}{
+		{
+			name:     "Form without explode",
+			obj:      map[string]interface{}{"a": []interface{}{"x", "y"}},
+			style:    ParamStyle{Style: StyleForm},
+			expected: "a=x,y",
+		},
+		{
+			name:     "Form with explode",
+			obj:      map[string]interface{}{"a": []interface{}{"x", "y"}},
+			style:    ParamStyle{Style: StyleForm, Explode: true},
+			expected: "a=x&a=y",
+		},
+		{
+			name:     "Form object without explode",
+			obj:      map[string]interface{}{"a": map[string]interface{}{"R": "100", "G": "200"}},
+			style:    ParamStyle{Style: StyleForm},
+			expected: "a=G,200,R,100",
+		},
+		{
+			name:     "Form object with explode",
+			obj:      map[string]interface{}{"a": map[string]interface{}{"R": "100", "G": "200"}},
+			style:    ParamStyle{Style: StyleForm, Explode: true},
+			expected: "G=200&R=100",
+		},
+		{
+			name:     "Space delimited",
+			obj:      map[string]interface{}{"a": []string{"x", "y"}},
+			style:    ParamStyle{Style: StyleSpaceDelimited},
+			expected: "a=x%20y",
+		},
+		{
+			name:     "Pipe delimited",
+			obj:      map[string]interface{}{"a": []interface{}{"x", "y"}},
+			style:    ParamStyle{Style: StylePipeDelimited},
+			expected: "a=x|y",
+		},
+		{
+			name:     "Deep object",
+			obj:      map[string]interface{}{"a": map[string]interface{}{"b": "c"}},
+			style:    ParamStyle{Style: StyleDeepObject, Explode: true},
+			expected: "a[b]=c",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Stringify(tt.obj, &StringifyOptions{EncodeValuesOnly: true, Styles: map[string]ParamStyle{"a": tt.style}})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, res)
+		})
+	}
+}
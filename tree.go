@@ -0,0 +1,222 @@
+package goqs
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// NodeKind identifies the kind of value a Node holds.
+type NodeKind int
+
+const (
+	NullNode NodeKind = iota
+	StringNode
+	NumberNode
+	ArrayNode
+	ObjectNode
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case NullNode:
+		return "null"
+	case StringNode:
+		return "string"
+	case NumberNode:
+		return "number"
+	case ArrayNode:
+		return "array"
+	case ObjectNode:
+		return "object"
+	}
+	return "unknown"
+}
+
+// Node is a value of a parsed query tree. Methods are safe to call on a nil
+// Node, so lookups can be chained: tree.Get("user").Get("name").String().
+type Node struct {
+	kind     NodeKind
+	str      string
+	num      float64
+	items    []*Node
+	keys     []string
+	children map[string]*Node
+}
+
+var numberPattern = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][+-]?\d+)?$`)
+
+// ParseTree parses str into a Node tree. Objects keep the order in which
+// keys first appear in the query. When ParseNumbers is set, numeric leaves
+// become NumberNode values.
+func ParseTree(str string, opts *ParseOptions) (*Node, error) {
+	result, err := ParseOrdered(str, opts)
+	if err != nil {
+		return nil, err
+	}
+	parseNumbers := opts != nil && opts.ParseNumbers
+	return newNode(result, parseNumbers), nil
+}
+
+func newNode(value interface{}, parseNumbers bool) *Node {
+	switch v := value.(type) {
+	case nil:
+		return &Node{kind: NullNode}
+	case string:
+		if parseNumbers && numberPattern.MatchString(v) {
+			if num, err := strconv.ParseFloat(v, 64); err == nil {
+				return &Node{kind: NumberNode, num: num, str: v}
+			}
+		}
+		return &Node{kind: StringNode, str: v}
+	case int:
+		return &Node{kind: NumberNode, num: float64(v), str: strconv.Itoa(v)}
+	case float64:
+		return &Node{kind: NumberNode, num: v, str: AsString(v)}
+	case []interface{}:
+		n := &Node{kind: ArrayNode, items: make([]*Node, len(v))}
+		for i, item := range v {
+			n.items[i] = newNode(item, parseNumbers)
+		}
+		return n
+	case *OrderedMap:
+		n := &Node{kind: ObjectNode, children: map[string]*Node{}}
+		for _, k := range v.keys {
+			n.keys = append(n.keys, k)
+			n.children[k] = newNode(v.values[k], parseNumbers)
+		}
+		return n
+	case map[string]interface{}:
+		n := &Node{kind: ObjectNode, children: map[string]*Node{}}
+		for k := range v {
+			n.keys = append(n.keys, k)
+		}
+		sort.Strings(n.keys)
+		for _, k := range n.keys {
+			n.children[k] = newNode(v[k], parseNumbers)
+		}
+		return n
+	case map[int]interface{}:
+		indices := make([]int, 0, len(v))
+		for i := range v {
+			indices = append(indices, i)
+		}
+		sort.Ints(indices)
+		n := &Node{kind: ObjectNode, children: map[string]*Node{}}
+		for _, i := range indices {
+			k := strconv.Itoa(i)
+			n.keys = append(n.keys, k)
+			n.children[k] = newNode(v[i], parseNumbers)
+		}
+		return n
+	}
+	return &Node{kind: StringNode, str: AsString(value)}
+}
+
+// Kind returns the kind of n. A nil Node is a NullNode.
+func (n *Node) Kind() NodeKind {
+	if n == nil {
+		return NullNode
+	}
+	return n.kind
+}
+
+// String returns the text of a string or number node, and "" otherwise.
+func (n *Node) String() string {
+	if n == nil {
+		return ""
+	}
+	return n.str
+}
+
+// Number returns the value of a number node.
+func (n *Node) Number() (float64, bool) {
+	if n == nil || n.kind != NumberNode {
+		return 0, false
+	}
+	return n.num, true
+}
+
+// Get follows path through object keys and array indices, returning nil when
+// a segment is missing.
+func (n *Node) Get(path ...string) *Node {
+	current := n
+	for _, segment := range path {
+		if current == nil {
+			return nil
+		}
+		switch current.kind {
+		case ObjectNode:
+			current = current.children[segment]
+		case ArrayNode:
+			index, err := strconv.Atoi(segment)
+			if err != nil {
+				return nil
+			}
+			current = current.Index(index)
+		default:
+			return nil
+		}
+	}
+	return current
+}
+
+// Len returns the number of items of an array or keys of an object.
+func (n *Node) Len() int {
+	if n == nil {
+		return 0
+	}
+	switch n.kind {
+	case ArrayNode:
+		return len(n.items)
+	case ObjectNode:
+		return len(n.keys)
+	}
+	return 0
+}
+
+// Index returns the i-th item of an array, or nil.
+func (n *Node) Index(i int) *Node {
+	if n == nil || n.kind != ArrayNode || i < 0 || i >= len(n.items) {
+		return nil
+	}
+	return n.items[i]
+}
+
+// Keys returns the keys of an object in order.
+func (n *Node) Keys() []string {
+	if n == nil || n.kind != ObjectNode {
+		return nil
+	}
+	return append([]string{}, n.keys...)
+}
+
+// Walk calls fn for n and every descendant, depth first and in order, with
+// the path leading to each node. Walking stops at the first error.
+func (n *Node) Walk(fn func(path []string, node *Node) error) error {
+	return n.walk(nil, fn)
+}
+
+func (n *Node) walk(path []string, fn func(path []string, node *Node) error) error {
+	if n == nil {
+		return nil
+	}
+	if err := fn(path, n); err != nil {
+		return err
+	}
+	switch n.kind {
+	case ArrayNode:
+		for i, item := range n.items {
+			if err := item.walk(append(append([]string{}, path...), strconv.Itoa(i)), fn); err != nil {
+				return err
+			}
+		}
+	case ObjectNode:
+		for _, k := range n.keys {
+			if err := n.children[k].walk(append(append([]string{}, path...), k), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
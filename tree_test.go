@@ -0,0 +1,63 @@
+package goqs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTree(t *testing.T) {
+	tree, err := ParseTree("user[name]=Alice&user[age]=30&tags[]=a&tags[]=b&empty", &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, ParseNumbers: true, StrictNullHandling: true})
+	assert.NoError(t, err)
+
+	assert.Equal(t, ObjectNode, tree.Kind())
+	assert.Equal(t, []string{"user", "tags", "empty"}, tree.Keys())
+	assert.Equal(t, "Alice", tree.Get("user", "name").String())
+
+	age, ok := tree.Get("user", "age").Number()
+	assert.True(t, ok)
+	assert.Equal(t, 30.0, age)
+
+	tags := tree.Get("tags")
+	assert.Equal(t, ArrayNode, tags.Kind())
+	assert.Equal(t, 2, tags.Len())
+	assert.Equal(t, "b", tags.Index(1).String())
+	assert.Equal(t, "a", tree.Get("tags", "0").String())
+
+	assert.Equal(t, NullNode, tree.Get("empty").Kind())
+
+	assert.Nil(t, tree.Get("user", "missing", "deeper"))
+	assert.Equal(t, NullNode, tree.Get("nope").Kind())
+	assert.Equal(t, "", tree.Get("nope").String())
+}
+
+func TestParseTreeWithoutNumbers(t *testing.T) {
+	tree, err := ParseTree("a=30&empty", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, StringNode, tree.Get("a").Kind())
+	assert.Equal(t, StringNode, tree.Get("empty").Kind())
+}
+
+func TestNodeWalk(t *testing.T) {
+	tree, err := ParseTree("a[b]=1&c[]=2", nil)
+	assert.NoError(t, err)
+
+	var visited []string
+	err = tree.Walk(func(path []string, node *Node) error {
+		visited = append(visited, strings.Join(path, ".")+":"+node.Kind().String())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{":object", "a:object", "a.b:string", "c:array", "c.0:string"}, visited)
+
+	stop := errors.New("stop")
+	err = tree.Walk(func(path []string, node *Node) error {
+		if node.Kind() == ArrayNode {
+			return stop
+		}
+		return nil
+	})
+	assert.Equal(t, stop, err)
+}
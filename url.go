@@ -0,0 +1,78 @@
+package goqs
+
+import (
+	"net/url"
+	"strings"
+)
+
+// URLOptions configures ParseURL and ParseFromURL.
+type URLOptions struct {
+	// Parse holds the options used for the query and fragment, the defaults
+	// if nil.
+	Parse *ParseOptions
+	// ParseFragment also parses the fragment with query syntax. When the
+	// fragment holds a route, as in "#/search?q=x", only the part after the
+	// first "?" is parsed.
+	ParseFragment bool
+}
+
+// ParsedURL is the result of ParseURL. Fragment is nil unless
+// URLOptions.ParseFragment is set.
+type ParsedURL struct {
+	URL      *url.URL
+	Query    map[string]interface{}
+	Fragment map[string]interface{}
+}
+
+// ParseURL parses rawURL and its query string, and optionally its fragment.
+func ParseURL(rawURL string, opts *URLOptions) (*ParsedURL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFromURL(u, opts)
+}
+
+// ParseFromURL parses the query string, and optionally the fragment, of u.
+func ParseFromURL(u *url.URL, opts *URLOptions) (*ParsedURL, error) {
+	var options URLOptions
+	if opts != nil {
+		options = *opts
+	}
+
+	query, err := Parse(u.RawQuery, options.Parse)
+	if err != nil {
+		return nil, err
+	}
+	parsed := &ParsedURL{URL: u, Query: query}
+
+	if options.ParseFragment {
+		fragment := u.EscapedFragment()
+		if i := strings.IndexByte(fragment, '?'); i >= 0 {
+			fragment = fragment[i+1:]
+		}
+		parsed.Fragment, err = Parse(fragment, options.Parse)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parsed, nil
+}
+
+// SetQuery replaces the query string of u with obj stringified using opts.
+// Keys and values are always percent-encoded, so SkipEncode and
+// EncodeValuesOnly are ignored, as is AddQueryPrefix.
+func SetQuery(u *url.URL, obj interface{}, opts *StringifyOptions) error {
+	options := normalizeStringifyOptions(opts)
+	options.AddQueryPrefix = false
+	options.SkipEncode = false
+	options.EncodeValuesOnly = false
+
+	query, err := Stringify(obj, &options)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = query
+	u.ForceQuery = false
+	return nil
+}
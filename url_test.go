@@ -0,0 +1,110 @@
+package goqs
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseURL(t *testing.T) {
+	parsed, err := ParseURL("https://example.com/items?filter[status]=open&tags[]=a&tags[]=b#top", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/items", parsed.URL.Path)
+	assert.Equal(t, map[string]interface{}{
+		"filter": map[string]interface{}{"status": "open"},
+		"tags":   []interface{}{"a", "b"},
+	}, parsed.Query)
+	assert.Nil(t, parsed.Fragment)
+
+	_, err = ParseURL("http://[::1", nil)
+	assert.Error(t, err)
+}
+
+func TestParseURLFragment(t *testing.T) {
+	tests := []struct {
+		name     string
+		url      string
+		expected map[string]interface{}
+	}{
+		{
+			name:     "query syntax",
+			url:      "https://example.com/#a[b]=c&d=e%20f",
+			expected: map[string]interface{}{"a": map[string]interface{}{"b": "c"}, "d": "e f"},
+		},
+		{
+			name:     "router path",
+			url:      "https://example.com/app?x=1#/search?q=go&page[size]=10",
+			expected: map[string]interface{}{"q": "go", "page": map[string]interface{}{"size": "10"}},
+		},
+		{
+			name:     "empty",
+			url:      "https://example.com/",
+			expected: map[string]interface{}{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseURL(tt.url, &URLOptions{ParseFragment: true})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, parsed.Fragment)
+		})
+	}
+}
+
+func TestSetQuery(t *testing.T) {
+	u, _ := url.Parse("https://example.com/items?old=1#frag")
+
+	err := SetQuery(u, map[string]interface{}{
+		"filter": map[string]interface{}{"status": "open"},
+		"tags":   []interface{}{"a b"},
+	}, &StringifyOptions{AddQueryPrefix: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/items?filter%5Bstatus%5D=open&tags%5B0%5D=a%20b#frag", u.String())
+
+	assert.Error(t, SetQuery(u, "x", nil))
+}
+
+func TestSetQueryAlwaysEncodes(t *testing.T) {
+	obj := map[string]interface{}{
+		"q":    "a&admin=1",
+		"tags": []interface{}{"x y", "#1"},
+	}
+
+	tests := []struct {
+		name     string
+		options  *StringifyOptions
+		expected string
+	}{
+		{
+			name:     "defaults",
+			expected: "q=a%26admin%3D1&tags%5B0%5D=x%20y&tags%5B1%5D=%231",
+		},
+		{
+			name:     "brackets",
+			options:  &StringifyOptions{ArrayFormat: ArrayFormatBrackets},
+			expected: "q=a%26admin%3D1&tags%5B%5D=x%20y&tags%5B%5D=%231",
+		},
+		{
+			name:     "SkipEncode ignored",
+			options:  &StringifyOptions{SkipEncode: true},
+			expected: "q=a%26admin%3D1&tags%5B0%5D=x%20y&tags%5B1%5D=%231",
+		},
+		{
+			name:     "EncodeValuesOnly ignored",
+			options:  &StringifyOptions{EncodeValuesOnly: true},
+			expected: "q=a%26admin%3D1&tags%5B0%5D=x%20y&tags%5B1%5D=%231",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, _ := url.Parse("https://example.com/items")
+			assert.NoError(t, SetQuery(u, obj, tt.options))
+			assert.Equal(t, tt.expected, u.RawQuery)
+			assert.Equal(t, "a&admin=1", u.Query().Get("q"))
+			assert.NotContains(t, u.Query(), "admin")
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package goqs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// FromValues builds a nested result from already decoded values, such as
+// r.Form, applying the same key options and parameter limit as Parse. Values
+// are not unescaped again; a ValueDecoder receives them with an identity
+// DecodeFunc. Keys are processed in key order, with array indices compared as
+// numbers, and parameters past ParameterLimit are dropped.
+func FromValues(values url.Values, opts *ParseOptions) (result map[string]interface{}, err error) {
+	defer recoverParseError(&result, &err)
+
+	options := normalizeParseOptions(opts)
+	limit := parameterLimit(options)
+
+	keys := make([]string, 0, len(values))
+	count := 0
+	for k := range values {
+		if k != "" {
+			keys = append(keys, k)
+			count += len(values[k])
+		}
+	}
+	if options.ThrowOnLimitExceeded && count > limit {
+		return nil, parameterLimitError(limit)
+	}
+	sortParamKeys(keys, options)
+
+	var allowed [][]string
+	if options.AllowedKeys != nil {
+		allowed = compileKeyPaths(options.AllowedKeys)
+	}
+
+	identity := func(s string) string { return s }
+	parsed := []parsedValue{}
+	for _, k := range keys {
+		if !keyAllowed(allowed, k, options) {
+			continue
+		}
+		for _, v := range values[k] {
+			if len(parsed) == limit {
+				break
+			}
+			var value interface{} = v
+			if options.ValueDecoder != nil {
+				typed, err := options.ValueDecoder(v, identity, options.Charset, chainPath(splitKey(k, options)))
+				if err != nil {
+					return nil, fmt.Errorf("decoding value of %q: %w", k, err)
+				}
+				value = markDecoded(typed)
+			}
+			parsed = append(parsed, parsedValue{index: len(parsed), key: k, value: value})
+		}
+	}
+	return buildObject(parsed, options)
+}
+
+// ToValues flattens a nested result into url.Values with bracketed keys,
+// writing arrays in arrayFormat, ArrayFormatIndices if empty. Null values
+// become empty strings.
+func ToValues(result map[string]interface{}, arrayFormat ArrayFormat) url.Values {
+	options := normalizeStringifyOptions(&StringifyOptions{ArrayFormat: arrayFormat, SkipEncode: true})
+
+	values := url.Values{}
+	for _, pair := range appendPairs(nil, "", result, options) {
+		if pair.null {
+			values.Add(pair.key, "")
+			continue
+		}
+		sep := pair.sep
+		if sep == "" {
+			sep = ","
+		}
+		values.Add(pair.key, strings.Join(pair.values, sep))
+	}
+	return values
+}
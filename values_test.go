@@ -0,0 +1,92 @@
+package goqs
+
+import (
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromValues(t *testing.T) {
+	values := url.Values{
+		"user[name]": {"Alice & Bob"},
+		"tags[]":     {"a+b", "100%"},
+		"page":       {"1", "2"},
+		"":           {"ignored"},
+	}
+
+	res, err := FromValues(values, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{"name": "Alice & Bob"},
+		"tags": []interface{}{"a+b", "100%"},
+		"page": []interface{}{"1", "2"},
+	}, res)
+
+	res, err = FromValues(url.Values{"a.b": {"1"}}, &ParseOptions{AllowDots: true, Depth: 5, ArrayLimit: 20, ParseArrays: true})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": map[string]interface{}{"b": "1"}}, res)
+}
+
+func TestToValues(t *testing.T) {
+	result := map[string]interface{}{
+		"user": map[string]interface{}{"name": "Alice"},
+		"tags": []interface{}{"a", "b"},
+		"none": nil,
+	}
+
+	assert.Equal(t, url.Values{
+		"user[name]": {"Alice"},
+		"tags[0]":    {"a"},
+		"tags[1]":    {"b"},
+		"none":       {""},
+	}, ToValues(result, ""))
+
+	assert.Equal(t, url.Values{
+		"user[name]": {"Alice"},
+		"tags[]":     {"a", "b"},
+		"none":       {""},
+	}, ToValues(result, ArrayFormatBrackets))
+
+	assert.Equal(t, url.Values{
+		"user[name]": {"Alice"},
+		"tags":       {"a,b"},
+		"none":       {""},
+	}, ToValues(result, ArrayFormatComma))
+}
+
+func TestValuesRoundTrip(t *testing.T) {
+	result, err := Parse("a[b][0]=x&a[b][1]=y&c=d%26e", nil)
+	assert.NoError(t, err)
+
+	res, err := FromValues(ToValues(result, ArrayFormatIndices), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, result, res)
+}
+
+func TestValuesRoundTripLongArray(t *testing.T) {
+	items := []interface{}{}
+	for i := 0; i < 12; i++ {
+		items = append(items, strconv.Itoa(i))
+	}
+	result := map[string]interface{}{"a": items}
+
+	res, err := FromValues(ToValues(result, ArrayFormatIndices), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, result, res)
+}
+
+func TestFromValuesParameterLimit(t *testing.T) {
+	values := url.Values{"a": {"1", "2"}, "b": {"3"}}
+
+	res, err := FromValues(values, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, ParameterLimit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"a": []interface{}{"1", "2"}}, res)
+
+	_, err = FromValues(values, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, ParameterLimit: 2, ThrowOnLimitExceeded: true})
+	assert.EqualError(t, err, "Parameter limit exceeded. Only 2 parameters allowed.")
+
+	_, err = FromValues(values, &ParseOptions{Depth: 5, ArrayLimit: 20, ParseArrays: true, ParameterLimit: 3, ThrowOnLimitExceeded: true})
+	assert.NoError(t, err)
+}
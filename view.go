@@ -0,0 +1,101 @@
+package goqs
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// Clone returns a deep copy of a parse result.
+func Clone(result map[string]interface{}) map[string]interface{} {
+	clone, _ := cloneValue(result).(map[string]interface{})
+	return clone
+}
+
+// View is a read-only parse result. It holds a private copy of the result
+// and its accessors return copies, so a View can be cached and shared by
+// concurrent goroutines.
+type View struct {
+	data map[string]interface{}
+}
+
+// NewView returns a View of a copy of result.
+func NewView(result map[string]interface{}) View {
+	data := Clone(result)
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	return View{data: data}
+}
+
+// Get returns a copy of the value at path.
+func (v View) Get(path string) (interface{}, bool) {
+	value, ok := Get(v.data, path)
+	return cloneValue(value), ok
+}
+
+// Has reports whether path holds a value.
+func (v View) Has(path string) bool {
+	_, ok := Get(v.data, path)
+	return ok
+}
+
+// GetString returns the scalar at path as a string.
+func (v View) GetString(path string) (string, error) {
+	return GetString(v.data, path)
+}
+
+// GetInt returns the scalar at path as an int.
+func (v View) GetInt(path string) (int, error) {
+	return GetInt(v.data, path)
+}
+
+// GetFloat returns the scalar at path as a float64.
+func (v View) GetFloat(path string) (float64, error) {
+	return GetFloat(v.data, path)
+}
+
+// GetBool returns the scalar at path as a bool.
+func (v View) GetBool(path string) (bool, error) {
+	return GetBool(v.data, path)
+}
+
+// GetStrings returns the array at path as strings.
+func (v View) GetStrings(path string) ([]string, error) {
+	return GetStrings(v.data, path)
+}
+
+// GetTime returns the scalar at path as a time parsed with layout.
+func (v View) GetTime(path string, layout string) (time.Time, error) {
+	return GetTime(v.data, path, layout)
+}
+
+// Keys returns the top-level keys in lexical order.
+func (v View) Keys() []string {
+	keys := make([]string, 0, len(v.data))
+	for k := range v.data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Len returns the number of top-level keys.
+func (v View) Len() int {
+	return len(v.data)
+}
+
+// Map returns a copy of the result.
+func (v View) Map() map[string]interface{} {
+	return Clone(v.data)
+}
+
+// Stringify serializes the result like Stringify.
+func (v View) Stringify(opts *StringifyOptions) (string, error) {
+	return Stringify(v.data, opts)
+}
+
+// MarshalJSON encodes the result as JSON.
+func (v View) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.data)
+}
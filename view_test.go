@@ -0,0 +1,88 @@
+package goqs
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+	result, err := Parse("a[b][]=1&a[b][]=2&c=3", nil)
+	assert.NoError(t, err)
+
+	clone := Clone(result)
+	assert.Equal(t, result, clone)
+
+	assert.NoError(t, Set(clone, "a[b][0]", "changed"))
+	assert.NoError(t, Set(clone, "d", "4"))
+	assert.Equal(t, []interface{}{"1", "2"}, result["a"].(map[string]interface{})["b"])
+	assert.NotContains(t, result, "d")
+
+	assert.Nil(t, Clone(nil))
+}
+
+func TestView(t *testing.T) {
+	result, err := Parse("user[name]=Alice&tags[]=a&tags[]=b&page=2", nil)
+	assert.NoError(t, err)
+
+	view := NewView(result)
+	assert.NoError(t, Set(result, "user[name]", "Bob"))
+
+	name, err := view.GetString("user.name")
+	assert.NoError(t, err)
+	assert.Equal(t, "Alice", name)
+
+	page, err := view.GetInt("page")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, page)
+
+	user, ok := view.Get("user")
+	assert.True(t, ok)
+	user.(map[string]interface{})["name"] = "Eve"
+	name, _ = view.GetString("user.name")
+	assert.Equal(t, "Alice", name)
+
+	m := view.Map()
+	m["page"] = "3"
+	page, _ = view.GetInt("page")
+	assert.Equal(t, 2, page)
+
+	assert.True(t, view.Has("tags"))
+	assert.Equal(t, []string{"page", "tags", "user"}, view.Keys())
+	assert.Equal(t, 3, view.Len())
+
+	query, err := view.Stringify(&StringifyOptions{SkipEncode: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "page=2&tags[0]=a&tags[1]=b&user[name]=Alice", query)
+
+	data, err := json.Marshal(view)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"page":"2","tags":["a","b"],"user":{"name":"Alice"}}`, string(data))
+}
+
+func TestViewConcurrentReads(t *testing.T) {
+	result, err := Parse("a[b][c]=1&d[]=x&d[]=y", nil)
+	assert.NoError(t, err)
+	view := NewView(result)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				value, _ := view.Get("a")
+				value.(map[string]interface{})["b"] = nil
+				_, _ = view.Stringify(nil)
+				_, _ = view.GetStrings("d")
+			}
+		}()
+	}
+	wg.Wait()
+
+	c, err := view.GetString("a.b.c")
+	assert.NoError(t, err)
+	assert.Equal(t, "1", c)
+}